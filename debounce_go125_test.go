@@ -0,0 +1,84 @@
+//go:build go1.25
+
+package main
+
+import (
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// TestDebounce runs in a synctest bubble, where time only advances when every
+// goroutine in the bubble is blocked, so the emitted values and their times
+// are exact. testing/synctest.Test was added in Go 1.25.
+func TestDebounce(t *testing.T) {
+	const quiet = 20 * time.Millisecond
+	type emission struct {
+		v  int
+		at time.Duration
+	}
+
+	for _, tt := range []struct {
+		name string
+		// send is called with the input channel and closes it when done.
+		send func(in chan<- int)
+		want []emission
+	}{
+		{
+			name: "burst, gap, burst",
+			send: func(in chan<- int) {
+				for _, v := range []int{1, 2, 3, 4, 5} {
+					in <- v
+					time.Sleep(time.Millisecond)
+				}
+				time.Sleep(60 * time.Millisecond)
+				for _, v := range []int{6, 7, 8} {
+					in <- v
+					time.Sleep(time.Millisecond)
+				}
+				time.Sleep(60 * time.Millisecond)
+				close(in)
+			},
+			// The last value of each burst arrives 4ms and 67ms in, and is
+			// emitted one quiet period later.
+			want: []emission{{5, 4*time.Millisecond + quiet}, {8, 67*time.Millisecond + quiet}},
+		},
+		{
+			name: "gaps shorter than the quiet period",
+			send: func(in chan<- int) {
+				for v := range 5 {
+					in <- v
+					time.Sleep(quiet - time.Millisecond)
+				}
+				time.Sleep(quiet)
+				close(in)
+			},
+			want: []emission{{4, 4*(quiet-time.Millisecond) + quiet}},
+		},
+		{
+			name: "close flushes the pending value",
+			send: func(in chan<- int) {
+				in <- 1
+				in <- 2
+				close(in)
+			},
+			want: []emission{{2, 0}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				start := time.Now()
+				in := make(chan int)
+				go tt.send(in)
+				var got []emission
+				for v := range Debounce(in, quiet) {
+					got = append(got, emission{v, time.Since(start)})
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("Debounce emitted %v, want %v", got, tt.want)
+				}
+			})
+		})
+	}
+}
@@ -4,7 +4,7 @@
 // It includes:
 // - Generic type aliases
 // - CGO improve
-// - Improved finalizers
+// - Improved finalizers: runtime.AddCleanup
 // - Crypto packages: HKDF, PBKDF2, SHA3
 // - Directory-limited filesystem access
 // - Bytes and strings iterators
@@ -14,36 +14,161 @@
 // - Runtime GOROOT deprecation notice
 // - Text template: Range over integer sequence
 // - math/big: Encoding TextAppender
-// - math/rand: Using a Rand instance
+// - math/rand and math/rand/v2: Seeded Rand instances
 // - sync.Map improvements
 // - log/slog: DiscardHandler demonstration
 // - time: Encoding Interfaces
 // - experimental testing/synctest
 // - go/types Iterator Methods
 // - maphash: Comparable and WriteComparable
+// - net/http: Client retry with context deadline
+// - go/doc/comment: Rendering doc comments
+// - maphash: Hashing slices
+// - crypto/tls: Exporting keying material
+// - Generics with math/bits: Bit manipulation helpers
+// - net/http: Request.PathValue wildcards
+// - time: Named layout constants
+// - Iterators and generics: A generic event bus
+// - crypto/mlkem: Post-quantum key encapsulation
+// - io/fs: Stat and ModTime through os.Root.FS
+// - sync.Map and sync.OnceValue: Race-free lazy initialization
+// - encoding/json: Preserving large numbers with json.Number
+// - math/rand/v2: Retry with exponential backoff and jitter
+// - go/types: Type checking with an importer
+// - Streaming file encryption: os.Root, crypto/hkdf, and AES-CTR
+// - slices: Min, Max, MinFunc, and MaxFunc
+// - net/netip: Addresses as map keys
+// - strings: SplitSeq against Split
+// - crypto/tls: Minimum version and cipher suite policy
+// - Generic type aliases: Functional options
+// - runtime/debug: Soft memory limit
+// - encoding/json: Custom date format via a wrapper type
+// - go/scanner: Tokenizing Go source
+// - sync.WaitGroup.Go across toolchains
+// - Hashing a directory tree: os.Root.FS and crypto/sha3
+// - net/url: JoinPath and ResolveReference
+// - reflect: Value.Clear
+// - Iterators and goroutines: A bounded worker pool
+// - time: Garbage collection of unstopped timers
+// - crypto/hmac: Signed API tokens
+// - encoding/json: MarshalIndent and map key order
+// - net/netip: Migrating from net.ParseCIDR to netip.ParsePrefix
+// - Generics and sync.Map: Memoization
+// - log/slog: Runtime level changes with LevelVar
+// - bytes.Reader: Random access over appended content
+// - go/importer: Introspecting a standard library package
+// - Generics and iterators: A set type
+// - crypto/x509: Verifying a chain through an intermediate
+// - Text template: break and continue in range
+// - io.MultiWriter: Tee-ing output to a writer and a buffer
+// - maphash: Seeds and hash stability
+// - Generics and iterators: A ring buffer
+// - encoding: BinaryAppender over JSON via base64
+// - slices: Equal and EqualFunc
+// - net/http: Streaming request bodies
+// - go/types: Struct layout with SizesFor
+// - crypto/rand: Injecting the randomness source
+// - time and encoding/json: Zero times and omitzero
+// - Generics and channels: Pipeline stages
+// - log/slog: TextHandler vs JSONHandler and ReplaceAttr
+// - runtime: AddCleanup alongside SetFinalizer
+// - encoding/json: Streaming JSON Lines into an os.Root file
+// - os.Root: Reading user-supplied paths safely
+// - math/big: Formatting in bases 2 through 62
+// - Generics and timers: Debouncing a channel
+// - strings.Lines and strings.Cut: Parsing HTTP-style headers
+// - crypto: Selecting a hash by name
+// - Iterators: Collecting from an unbounded sequence
+// - time: Converting one instant across zones
+// - runtime.AddCleanup: Releasing forgotten handles
+// - Generics and sync.Pool: A typed pool
+// - encoding/json: Valid, Compact, and Indent
+// - os.Root: Classifying errors
+// - Generics and iterators: A prefix trie
+// - crypto/subtle: Constant-time selection
+// - runtime.Stack: Capturing a goroutine dump
+// - net/http: Serving an os.Root with FileServerFS
+// - slices and cmp: Deterministic multi-key sorting
+// - Generics and channels: A future
+// - encoding/json: Enforcing invariants in UnmarshalJSON
+// - math/bits: A tiny hash mixer
+// - Generics and iterators: Graph traversal
+// - crypto/tls: An in-memory CA for tests
+// - strings.Replacer for single-pass multi-pattern replacement
+// - Pooled SHA-3 hashers with Pool[T]
 
 // To run the demo, ensure you have Go 1.24 installed and run:
-// go run go1.24_demo.go
+// go run .
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/mlkem"
 	"crypto/pbkdf2"
+	crand "crypto/rand"
 	"crypto/sha256"
 	"crypto/sha3"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"hash"
 	"hash/maphash"
+	"io"
+	"io/fs"
+	"iter"
+	"log/slog"
+	"maps"
+	"math"
 	"math/big"
+	"math/bits"
 	"math/rand"
+	randv2 "math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/netip"
+	"net/textproto"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
+	_ "time/tzdata"
 )
 
 // ----------------------------------------------------------------------------
@@ -53,9 +178,77 @@ import (
 // a generic alias MySlice[T] for []T.
 type MySlice[T any] = []T
 
-func demoGenericTypeAlias() {
+// MapSlice returns a new slice holding f applied to each element of s. The
+// result is never nil, even for a nil s.
+func MapSlice[T, U any](s MySlice[T], f func(T) U) MySlice[U] {
+	out := make(MySlice[U], 0, len(s))
+	for _, v := range s {
+		out = append(out, f(v))
+	}
+	return out
+}
+
+// FilterSlice returns a new slice holding the elements of s for which keep
+// returns true.
+func FilterSlice[T any](s MySlice[T], keep func(T) bool) MySlice[T] {
+	out := make(MySlice[T], 0, len(s))
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// ReduceSlice folds s into an accumulator, starting from init and calling f
+// for each element in order. The accumulator type need not match T.
+func ReduceSlice[T, A any](s MySlice[T], init A, f func(A, T) A) A {
+	acc := init
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// AllSlice returns an iterator over the index/value pairs of s, like
+// slices.All. It is a function rather than an All method because MySlice is
+// an alias for the unnamed type []T, and methods can only be declared on
+// named types; a defined type would lose the interchangeability with []T
+// that the alias exists for.
+func AllSlice[T any](s MySlice[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, v := range s {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func demoGenericTypeAlias(w io.Writer) error {
 	numbers := MySlice[int]{1, 2, 3, 4, 5}
-	fmt.Println("Generic Type Alias (MySlice[int]):", numbers)
+	fmt.Fprintln(w, "Generic Type Alias (MySlice[int]):", numbers)
+	labels := MapSlice(numbers, func(n int) string { return "#" + strconv.Itoa(n) })
+	fmt.Fprintf(w, "MapSlice to MySlice[string]: %q\n", labels)
+	empty := MapSlice(MySlice[int](nil), strconv.Itoa)
+	fmt.Fprintf(w, "MapSlice of nil: %q (len %d, nil: %t)\n", empty, len(empty), empty == nil)
+	evens := FilterSlice(numbers, func(n int) bool { return n%2 == 0 })
+	sum := ReduceSlice(evens, 0, func(acc, n int) int { return acc + n })
+	fmt.Fprintln(w, "FilterSlice evens:", evens, "ReduceSlice sum:", sum)
+	joined := ReduceSlice(numbers, "", func(acc string, n int) string { return acc + strconv.Itoa(n) })
+	fmt.Fprintf(w, "ReduceSlice into a string accumulator: %q\n", joined)
+	var collected MySlice[int]
+	for i, v := range AllSlice(numbers) {
+		if i != len(collected) {
+			return fmt.Errorf("AllSlice yielded out of order at index %d", i)
+		}
+		collected = append(collected, v)
+	}
+	if !slices.Equal(collected, numbers) {
+		return fmt.Errorf("AllSlice collected %v, want %v", collected, numbers)
+	}
+	fmt.Fprintln(w, "AllSlice collected back equals original:", collected)
+	return nil
 }
 
 // 2. CGO Improvements (Skipped Code Implementation)
@@ -72,252 +265,812 @@ func demoGenericTypeAlias() {
 // void c_function_nocallback(void* p) {}
 
 // ----------------------------------------------------------------------------
-// 3. Improved Finalizers (using runtime.SetFinalizer as a stand-in)
+// 3. Improved Finalizers: runtime.AddCleanup
 //
-// Go 1.24 introduces runtime.AddCleanup to attach multiple cleanups to an object.
-// Here we use runtime.SetFinalizer (the older API) to demonstrate finalization.
-func DemoFinalizers() {
-	// Wrap an int in a custom struct to show finalization.
-	type Holder struct {
-		Value int
+// Go 1.24 introduces runtime.AddCleanup to attach multiple cleanups to an
+// object. Unlike a finalizer, a cleanup never receives the object itself,
+// only a separate argument, so it cannot resurrect the object. The argument
+// must not point back at the object, or the object stays reachable and the
+// cleanup never runs; copying the fields the cleanup needs avoids that.
+// Objects smaller than 16 bytes without pointers may share an allocation
+// and are not guaranteed to be cleaned up, so Holder carries a name too.
+
+// Holder is the object the cleanup demo attaches cleanups to.
+type Holder struct {
+	Name  string
+	Value int
+}
+
+// holderCleanups attaches n cleanups to a new Holder, drops it, and returns
+// the message each cleanup sent, in the order they ran.
+func holderCleanups(n int) ([]string, error) {
+	holder := &Holder{Name: "answer", Value: 42}
+	done := make(chan string, n)
+	for i := range n {
+		runtime.AddCleanup(holder, func(v int) {
+			done <- fmt.Sprintf("cleanup %d released value %d", i+1, v)
+		}, holder.Value)
 	}
-	holder := &Holder{Value: 42}
-	// Set a finalizer on the holder.
-	runtime.SetFinalizer(holder, func(h *Holder) {
-		fmt.Println("Finalizer called for Holder with value:", h.Value)
-	})
+
 	// Remove our reference and force garbage collection.
 	holder = nil
 	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
+	var msgs []string
+	timeout := time.After(2 * time.Second)
+	for range n {
+		select {
+		case msg := <-done:
+			msgs = append(msgs, msg)
+		case <-timeout:
+			return msgs, fmt.Errorf("timed out waiting for cleanups, got: %v", msgs)
+		}
+	}
+	return msgs, nil
+}
+
+func DemoFinalizers(w io.Writer) error {
+	msgs, err := holderCleanups(2)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		fmt.Fprintln(w, msg)
+	}
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 4. Crypto Packages: HKDF, PBKDF2, SHA3
 //
-// This demo uses HKDF (from golang.org/x/crypto/hkdf for now),
-// PBKDF2, and SHA3-256.
-func DemoCryptoPackages() {
+// This demo uses the new crypto/hkdf, crypto/pbkdf2, and crypto/sha3
+// packages.
+
+// HKDFExpand derives length bytes of key material from secret using HKDF
+// with SHA-256 (RFC 5869), extracting with salt and expanding with info. It
+// returns an error if length is not positive or exceeds the HKDF limit of
+// 255*32 bytes.
+func HKDFExpand(secret, salt, info []byte, length int) ([]byte, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("hkdf expand: length must be positive, got %d", length)
+	}
+	if limit := 255 * sha256.Size; length > limit {
+		return nil, fmt.Errorf("hkdf expand: length %d exceeds the maximum of %d", length, limit)
+	}
+	return hkdf.Key(sha256.New, secret, salt, string(info), length)
+}
+
+// DeriveKey stretches password into a keyLen-byte key using PBKDF2 with
+// HMAC-SHA256 and the given salt and iteration count.
+func DeriveKey(password string, salt []byte, iterations, keyLen int) ([]byte, error) {
+	if iterations <= 0 {
+		return nil, fmt.Errorf("derive key: iterations must be positive, got %d", iterations)
+	}
+	if keyLen <= 0 {
+		return nil, fmt.Errorf("derive key: key length must be positive, got %d", keyLen)
+	}
+	return pbkdf2.Key(sha256.New, password, salt, iterations, keyLen)
+}
+
+// NewSHA3Hasher returns a streaming SHA3 hash with the given output size in
+// bits: 224, 256, 384, or 512. Data can be copied into it with io.Copy, so
+// large inputs never need to be held in memory.
+func NewSHA3Hasher(bits int) (hash.Hash, error) {
+	switch bits {
+	case 224:
+		return sha3.New224(), nil
+	case 256:
+		return sha3.New256(), nil
+	case 384:
+		return sha3.New384(), nil
+	case 512:
+		return sha3.New512(), nil
+	}
+	return nil, fmt.Errorf("sha3: unsupported digest size %d bits", bits)
+}
+
+// SHA3Digest returns the SHA3 digest of data with the given output size in
+// bits: 224, 256, 384, or 512.
+func SHA3Digest(data []byte, bits int) ([]byte, error) {
+	h, err := NewSHA3Hasher(bits)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// SHAKE returns outputLen bytes of SHAKE128 output for data, or of SHAKE256
+// output if shake256 is true. outputLen must be non-negative; like make, SHAKE
+// panics otherwise.
+func SHAKE(data []byte, outputLen int, shake256 bool) []byte {
+	h := sha3.NewSHAKE128()
+	if shake256 {
+		h = sha3.NewSHAKE256()
+	}
+	h.Write(data)
+	out := make([]byte, outputLen)
+	h.Read(out)
+	return out
+}
+
+func DemoCryptoPackages(w io.Writer) error {
 	// PBKDF2 and SHA3-256 demos
 	password := "my password"
 	salt := []byte("my salt")
-	pbkdf2Key, err := pbkdf2.Key(sha256.New, password, salt, 4096, 32)
+	pbkdf2Key, err := DeriveKey(password, salt, 4096, 32)
 	if err != nil {
-		fmt.Println("PBKDF2 error:", err)
-		return
+		return fmt.Errorf("PBKDF2: %w", err)
+	}
+	fmt.Fprintln(w, "Derived key (PBKDF2):", hex.EncodeToString(pbkdf2Key))
+
+	if _, err := DeriveKey(password, salt, 0, 32); err != nil {
+		fmt.Fprintln(w, "DeriveKey rejects bad parameters:", err)
+	} else {
+		return errors.New("DeriveKey with 0 iterations unexpectedly succeeded")
+	}
+
+	// HKDF demo
+	hkdfKey, err := HKDFExpand(pbkdf2Key, salt, []byte("demo encryption key"), 32)
+	if err != nil {
+		return fmt.Errorf("HKDF: %w", err)
+	}
+	fmt.Fprintln(w, "Derived key (HKDF):", hex.EncodeToString(hkdfKey))
+
+	if _, err := HKDFExpand(pbkdf2Key, nil, nil, 255*sha256.Size+1); err != nil {
+		fmt.Fprintln(w, "HKDFExpand rejects oversized output:", err)
+	} else {
+		return errors.New("HKDFExpand with oversized output unexpectedly succeeded")
 	}
-	fmt.Println("Derived key (PBKDF2):", hex.EncodeToString(pbkdf2Key))
 
 	// SHA3-256 demo
 	hasher := sha3.New256()
 	hasher.Write([]byte("hello world"))
 	digest := hasher.Sum(nil)
-	fmt.Println("SHA3-256 digest:", hex.EncodeToString(digest))
+	fmt.Fprintln(w, "SHA3-256 digest:", hex.EncodeToString(digest))
+
+	// Other SHA3 sizes and the SHAKE extendable-output functions
+	shake := SHAKE([]byte("hello world"), 16, true)
+	fmt.Fprintln(w, "SHAKE256 (16 bytes):", hex.EncodeToString(shake))
+	if _, err := SHA3Digest(nil, 128); err != nil {
+		fmt.Fprintln(w, "SHA3Digest rejects unsupported sizes:", err)
+	} else {
+		return errors.New("SHA3Digest with 128 bits unexpectedly succeeded")
+	}
+
+	// Streaming: NewSHA3Hasher returns a hash.Hash, so io.Copy can feed it
+	// from any reader.
+	const msg = "hello world, streamed"
+	streamer, err := NewSHA3Hasher(512)
+	if err != nil {
+		return fmt.Errorf("SHA3: %w", err)
+	}
+	if _, err := io.Copy(streamer, strings.NewReader(msg)); err != nil {
+		return fmt.Errorf("streaming hash: %w", err)
+	}
+	oneShot, err := SHA3Digest([]byte(msg), 512)
+	if err != nil {
+		return fmt.Errorf("SHA3: %w", err)
+	}
+	if !bytes.Equal(streamer.Sum(nil), oneShot) {
+		return errors.New("streamed SHA3-512 does not match the one-shot digest")
+	}
+	fmt.Fprintln(w, "Streamed SHA3-512:", hex.EncodeToString(oneShot))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 5. Directory-Limited Filesystem Access
 //
 // In Go 1.24 the new os.Root type (and related functions) let you limit
-// filesystem access to a directory. For this demo we simulate such behavior.
-func DemoDirectoryLimitedFS() {
-	// Create a temporary directory.
+// filesystem access to a directory. Every path passed to a Root method is
+// resolved inside that directory; ".." components and symlinks that would
+// lead outside it are rejected with an error.
+func DemoDirectoryLimitedFS(w io.Writer) error {
+	// Create a temporary directory holding a file that the sandbox, a
+	// subdirectory, must not be able to reach.
 	tempDir, err := os.MkdirTemp("", "demo-root")
 	if err != nil {
-		fmt.Println("Error creating temp directory:", err)
-		return
+		return fmt.Errorf("creating temp directory: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
-
-	// Create a file within the directory.
-	filePath := tempDir + "/example.txt"
-	if err := os.WriteFile(filePath, []byte("Hello from a limited FS!"), 0644); err != nil {
-		fmt.Println("Error writing file:", err)
-		return
+	if err := os.WriteFile(filepath.Join(tempDir, "outside.txt"), []byte("secret"), 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	sandboxDir := filepath.Join(tempDir, "sandbox")
+	if err := os.Mkdir(sandboxDir, 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
 	}
 
-	// Open the directory.
-	root, err := os.Open(tempDir)
+	root, err := os.OpenRoot(sandboxDir)
 	if err != nil {
-		fmt.Println("Error opening directory:", err)
-		return
+		return fmt.Errorf("opening root: %w", err)
 	}
 	defer root.Close()
 
-	entries, err := root.Readdir(0)
+	// Create a file within the root.
+	f, err := root.Create("example.txt")
 	if err != nil {
-		fmt.Println("Error reading directory:", err)
-		return
+		return fmt.Errorf("creating file: %w", err)
+	}
+	_, err = f.WriteString("Hello from a limited FS!")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	// Read it back and list the directory, both through the root.
+	f, err = root.Open("example.txt")
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	content, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	fmt.Fprintf(w, "Root.Open example.txt: %q\n", content)
+
+	dir, err := root.Open(".")
+	if err != nil {
+		return fmt.Errorf("opening directory: %w", err)
+	}
+	entries, err := dir.ReadDir(0)
+	dir.Close()
+	if err != nil {
+		return fmt.Errorf("reading directory: %w", err)
 	}
-	fmt.Println("Files in limited FS:")
+	fmt.Fprintln(w, "Files in limited FS:")
 	for _, entry := range entries {
-		fmt.Println(" -", entry.Name())
+		fmt.Fprintln(w, " -", entry.Name())
+	}
+
+	// The file next to the sandbox exists, but the root cannot reach it.
+	if _, err := root.Open("../outside.txt"); err != nil {
+		fmt.Fprintln(w, "Root.Open outside the root failed as expected:", err)
+	} else {
+		return errors.New("Root.Open outside the root unexpectedly succeeded")
+	}
+	if _, err := root.Create("../outside.txt"); err != nil {
+		fmt.Fprintln(w, "Root.Create outside the root failed as expected:", err)
+	} else {
+		return errors.New("Root.Create outside the root unexpectedly succeeded")
+	}
+
+	// os.Root confines mode changes to the directory as well.
+	before, err := root.Stat("example.txt")
+	if err != nil {
+		return fmt.Errorf("stating file: %w", err)
+	}
+	if err := rootChmod(root, "example.txt", 0o600); err != nil {
+		return fmt.Errorf("changing mode: %w", err)
+	}
+	after, err := root.Stat("example.txt")
+	if err != nil {
+		return fmt.Errorf("stating file: %w", err)
+	}
+	fmt.Fprintf(w, "Root.Chmod example.txt: %v -> %v\n", before.Mode(), after.Mode())
+
+	if err := rootChmod(root, "../outside.txt", 0o600); err != nil {
+		fmt.Fprintln(w, "Root.Chmod outside the root failed as expected:", err)
+	} else {
+		return errors.New("Root.Chmod outside the root unexpectedly succeeded")
+	}
+
+	// Multi-segment paths resolve within the root, ".." included, as long as
+	// they never climb above it.
+	for _, dir := range []string{"a", "a/b", "a/b/c"} {
+		if err := root.Mkdir(dir, 0o755); err != nil {
+			return fmt.Errorf("creating directory: %w", err)
+		}
+	}
+	nested, err := root.Create("a/b/c/file")
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	nested.Close()
+	for _, name := range []string{"a/b/c/file", "a/b/../b/c/file"} {
+		info, err := root.Stat(name)
+		if err != nil {
+			return fmt.Errorf("stating nested file: %w", err)
+		}
+		fmt.Fprintf(w, "Root.Stat(%q): name=%s mode=%v size=%d\n", name, info.Name(), info.Mode(), info.Size())
+	}
+	if _, err := root.Stat("a/../../escape"); err != nil {
+		fmt.Fprintln(w, "Root.Stat escaping through a nested path failed as expected:", err)
+	} else {
+		return errors.New("Root.Stat escaping through a nested path unexpectedly succeeded")
 	}
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 6. Bytes and Strings Iterators
 //
-// The new iterator-style functions (e.g. Lines, SplitSeq) make it easier
-// to work with byte slices and strings.
-func DemoBytesAndStringsIterators() {
-	text := []byte("line1\nline2\nline3\n")
-	fmt.Println("Iterating over lines (using bytes.Split):")
-	for _, line := range bytes.Split(text, []byte("\n")) {
-		if len(line) > 0 {
-			fmt.Println(string(line))
+// Lines, SplitSeq, and FieldsSeq in bytes and strings return iter.Seq values
+// instead of slices, so ranging over them walks the input lazily without
+// allocating a slice of substrings first.
+
+// CountNonEmptyLines reports the number of lines in s that contain anything
+// besides whitespace. A final line without a trailing newline still counts.
+func CountNonEmptyLines(s string) int {
+	n := 0
+	for line := range strings.Lines(s) {
+		if strings.TrimSpace(line) != "" {
+			n++
 		}
 	}
+	return n
+}
+
+func DemoBytesAndStringsIterators(w io.Writer) error {
+	text := "line1\nline2\n\nline3\n"
+	fmt.Fprintln(w, "Iterating over lines (using strings.Lines):")
+	for line := range strings.Lines(text) {
+		// Each line keeps its terminator.
+		fmt.Fprintf(w, "%q\n", line)
+	}
 
 	sample := "  foo   bar baz  "
-	fmt.Println("Iterating over fields (using strings.Fields):")
-	for _, field := range strings.Fields(sample) {
-		fmt.Println(field)
+	fmt.Fprintln(w, "Iterating over fields (using strings.FieldsSeq):")
+	for field := range strings.FieldsSeq(sample) {
+		fmt.Fprintln(w, field)
 	}
+
+	for _, s := range []string{text, "no trailing newline\nlast", "", "\n\n", " \t\n"} {
+		fmt.Fprintf(w, "CountNonEmptyLines(%q) = %d\n", s, CountNonEmptyLines(s))
+	}
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 7. New encoding Interfaces: TextAppender and BinaryAppender
 //
 // Types that already implement TextMarshaler now also implement the
-// TextAppender interface to append directly to a buffer.
+// TextAppender interface to append directly to a buffer; BinaryAppender is
+// the equivalent for BinaryMarshaler.
 type demoStruct struct {
 	Value int
 }
 
 // AppendText implements encoding.TextAppender for demoStruct.
-func (d demoStruct) AppendText(dst []byte) []byte {
-	return append(dst, fmt.Sprintf("demoStruct(%d)", d.Value)...)
+func (d demoStruct) AppendText(dst []byte) ([]byte, error) {
+	return fmt.Appendf(dst, "demoStruct(%d)", d.Value), nil
+}
+
+// AppendTextOf appends the text form of v to dst. The type parameter lets
+// callers pass any TextAppender without converting it to an interface, and
+// gives the demos below one code path instead of a type assertion each.
+func AppendTextOf[T encoding.TextAppender](v T, dst []byte) ([]byte, error) {
+	return v.AppendText(dst)
+}
+
+// AppendTextOrMarshal appends a text form of v to dst, using the first of
+// encoding.TextAppender, encoding.TextMarshaler, and fmt.Stringer that v
+// implements. Only the first avoids an intermediate allocation. It fails if
+// v implements none of them.
+func AppendTextOrMarshal(v any, dst []byte) ([]byte, error) {
+	switch v := v.(type) {
+	case encoding.TextAppender:
+		return v.AppendText(dst)
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return dst, err
+		}
+		return append(dst, text...), nil
+	case fmt.Stringer:
+		return append(dst, v.String()...), nil
+	}
+	return dst, fmt.Errorf("%T has no text form", v)
+}
+
+// markedText implements encoding.TextMarshaler and fmt.Stringer, to show
+// which one AppendTextOrMarshal prefers.
+type markedText string
+
+func (m markedText) MarshalText() ([]byte, error) { return []byte("text:" + m), nil }
+func (m markedText) String() string               { return "string:" + string(m) }
+
+// markedString implements only fmt.Stringer.
+type markedString string
+
+func (m markedString) String() string { return "string:" + string(m) }
+
+// AppendBinary implements encoding.BinaryAppender, appending Value as an
+// 8-byte big-endian int64.
+func (d demoStruct) AppendBinary(dst []byte) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(dst, uint64(int64(d.Value))), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *demoStruct) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("demoStruct: invalid binary length %d", len(data))
+	}
+	d.Value = int(int64(binary.BigEndian.Uint64(data)))
+	return nil
 }
 
-func DemoEncodingAppend() {
+var (
+	_ encoding.BinaryAppender    = demoStruct{}
+	_ encoding.BinaryUnmarshaler = (*demoStruct)(nil)
+)
+
+func DemoEncodingAppend(w io.Writer) error {
 	ds := demoStruct{Value: 123}
-	var buf []byte
-	// Use the TextAppender interface if available.
-	if appender, ok := interface{}(ds).(interface {
-		AppendText([]byte) []byte
-	}); ok {
-		buf = appender.AppendText(buf)
+	buf, err := AppendTextOf(ds, nil)
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	fmt.Fprintln(w, "Encoding append result:", string(buf))
+	buf, err = AppendTextOf(time.Date(2025, time.February, 11, 0, 0, 0, 0, time.UTC), append(buf, ' '))
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	fmt.Fprintln(w, "AppendTextOf demoStruct then time.Time:", string(buf))
+
+	for _, v := range []any{demoStruct{Value: 1}, markedText("a"), markedString("b")} {
+		out, err := AppendTextOrMarshal(v, nil)
+		if err != nil {
+			return fmt.Errorf("AppendTextOrMarshal(%T): %w", v, err)
+		}
+		fmt.Fprintf(w, "AppendTextOrMarshal(%T): %s\n", v, out)
+	}
+	if _, err := AppendTextOrMarshal(42, nil); err != nil {
+		fmt.Fprintln(w, "AppendTextOrMarshal(int) failed as expected:", err)
 	} else {
-		buf = append(buf, fmt.Sprintf("%v", ds)...)
+		return errors.New("AppendTextOrMarshal(int) unexpectedly succeeded")
+	}
+
+	for _, ds := range []demoStruct{{Value: 123}, {Value: -1}} {
+		bin, err := ds.AppendBinary([]byte("hdr:"))
+		if err != nil {
+			return fmt.Errorf("AppendBinary: %w", err)
+		}
+		var back demoStruct
+		if err := back.UnmarshalBinary(bin[len("hdr:"):]); err != nil {
+			return fmt.Errorf("UnmarshalBinary: %w", err)
+		}
+		if back != ds {
+			return fmt.Errorf("binary round trip of %v gave %v", ds, back)
+		}
+		fmt.Fprintf(w, "Binary append result: %x\n", bin)
 	}
-	fmt.Println("Encoding append result:", string(buf))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 8. go/net/netip: Encoding Interfaces
 //
-// netip.Addr now implements encoding.TextAppender.
-func DemoNetipEncoding() {
+// netip.Addr now implements encoding.TextAppender and
+// encoding.BinaryAppender. The binary form is the raw address: 4 bytes for
+// IPv4, 16 for IPv6, followed by the zone for a scoped IPv6 address.
+
+// AppendAddrBinary appends the binary form of a to dst. netip.Addr's
+// AppendBinary never fails, so there is no error to return.
+func AppendAddrBinary(a netip.Addr, dst []byte) []byte {
+	dst, _ = a.AppendBinary(dst)
+	return dst
+}
+
+// AppendPrefixText appends the text form of p to dst, such as
+// "192.0.2.0/24". Unlike Prefix.AppendText, which appends nothing for the
+// zero Prefix, it appends "invalid Prefix" as Prefix.String does, so a
+// missing value is visible in output.
+func AppendPrefixText(p netip.Prefix, dst []byte) []byte {
+	if !p.IsValid() {
+		return append(dst, p.String()...)
+	}
+	dst, _ = p.AppendText(dst)
+	return dst
+}
+
+// AppendAddrPortText is AppendPrefixText for netip.AddrPort, appending
+// "invalid AddrPort" for the zero value.
+func AppendAddrPortText(ap netip.AddrPort, dst []byte) []byte {
+	if !ap.IsValid() {
+		return append(dst, ap.String()...)
+	}
+	dst, _ = ap.AppendText(dst)
+	return dst
+}
+
+func DemoNetipEncoding(w io.Writer) error {
 	addr, err := netip.ParseAddr("192.0.2.1")
 	if err != nil {
-		fmt.Println("Error parsing IP:", err)
-		return
+		return fmt.Errorf("parsing IP: %w", err)
 	}
-	var buf []byte
-	// Use type assertion to check for TextAppender.
-	if appender, ok := interface{}(addr).(interface {
-		AppendText([]byte) []byte
-	}); ok {
-		buf = appender.AppendText(buf)
-	} else {
-		buf = []byte(addr.String())
+	buf, err := AppendTextOf(addr, nil)
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	fmt.Fprintln(w, "netip.Addr appended text:", string(buf))
+
+	for _, a := range []netip.Addr{addr, netip.MustParseAddr("2001:db8::1")} {
+		bin := AppendAddrBinary(a, nil)
+		want, err := a.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("MarshalBinary: %w", err)
+		}
+		if !bytes.Equal(bin, want) {
+			return fmt.Errorf("AppendAddrBinary(%v) = %x, MarshalBinary = %x", a, bin, want)
+		}
+		fmt.Fprintf(w, "netip.Addr %v appended binary (%d bytes): %x\n", a, len(bin), bin)
+	}
+
+	prefix := netip.MustParsePrefix("192.0.2.0/24")
+	text := AppendPrefixText(prefix, nil)
+	if back, err := netip.ParsePrefix(string(text)); err != nil || back != prefix {
+		return fmt.Errorf("netip.Prefix %v did not round trip through %q: %v", prefix, text, err)
+	}
+	fmt.Fprintln(w, "netip.Prefix appended text:", string(text))
+	addrPort := netip.MustParseAddrPort("[2001:db8::1]:443")
+	text = AppendAddrPortText(addrPort, nil)
+	if back, err := netip.ParseAddrPort(string(text)); err != nil || back != addrPort {
+		return fmt.Errorf("netip.AddrPort %v did not round trip through %q: %v", addrPort, text, err)
 	}
-	fmt.Println("netip.Addr appended text:", string(buf))
+	fmt.Fprintln(w, "netip.AddrPort appended text:", string(text))
+	fmt.Fprintf(w, "Zero values append %q and %q\n",
+		AppendPrefixText(netip.Prefix{}, nil), AppendAddrPortText(netip.AddrPort{}, nil))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 9. Regexp: TextAppender Interface
 //
 // Regular expressions now implement encoding.TextAppender.
-func DemoRegexpEncoding() {
+func DemoRegexpEncoding(w io.Writer) error {
 	re := regexp.MustCompile(`a*b`)
-	var buf []byte
-	if appender, ok := interface{}(re).(interface {
-		AppendText([]byte) []byte
-	}); ok {
-		buf = appender.AppendText(buf)
-	} else {
-		buf = []byte(re.String())
+	buf, err := AppendTextOf(re, nil)
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
 	}
-	fmt.Println("Regexp appended text:", string(buf))
+	fmt.Fprintln(w, "Regexp appended text:", string(buf))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 10. Runtime GOROOT Deprecation Notice
 //
 // runtime.GOROOT is now deprecated.
-func DemoRuntimeGOROOT() {
-	fmt.Println("Note: runtime.GOROOT is deprecated; use 'go env GOROOT' instead.")
+func DemoRuntimeGOROOT(w io.Writer) error {
+	fmt.Fprintln(w, "Note: runtime.GOROOT is deprecated; use 'go env GOROOT' instead.")
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 11. Text Template: Range over Integer Sequence
 //
-// Templates now support range-over-int. This demo uses a "seq" function.
-func DemoTextTemplate() {
-	tmplText := `Numbers: {{range $i := seq 1 5}}{{$i}} {{end}}`
-	tmpl, err := template.New("demo").Funcs(template.FuncMap{
-		"seq": func(start, end int) []int {
-			s := make([]int, 0, end-start+1)
-			for i := start; i <= end; i++ {
-				s = append(s, i)
-			}
-			return s
-		},
-	}).Parse(tmplText)
+// Templates now support range-over-int: {{range $i := N}} runs N times with
+// $i from 0 to N-1, with no helper function needed.
+
+var countTemplate = template.Must(template.New("count").Parse(`Numbers: {{range $i := .}}{{$i}} {{end}}`))
+
+// RenderCountTemplate renders the numbers 0 through n-1 by ranging over n in
+// a template.
+func RenderCountTemplate(n int) (string, error) {
+	var out bytes.Buffer
+	if err := countTemplate.Execute(&out, n); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// templateFuncs are the functions available to RenderTemplate.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"sortedKeys": func(m map[string]int) []string {
+		return slices.Sorted(maps.Keys(m))
+	},
+}
+
+// RenderTemplate parses text with templateFuncs and executes it with data,
+// returning the output or the parse or execution error.
+func RenderTemplate(text string, data any) (string, error) {
+	tmpl, err := template.New("render").Funcs(templateFuncs).Parse(text)
 	if err != nil {
-		fmt.Println("Error parsing template:", err)
-		return
+		return "", err
 	}
-	var tplOutput bytes.Buffer
-	if err := tmpl.Execute(&tplOutput, nil); err != nil {
-		fmt.Println("Error executing template:", err)
-		return
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func DemoTextTemplate(w io.Writer) error {
+	for _, n := range []int{5, 1, 0} {
+		out, err := RenderCountTemplate(n)
+		if err != nil {
+			return fmt.Errorf("executing template: %w", err)
+		}
+		fmt.Fprintf(w, "Template output for %d: %q\n", n, out)
+	}
+
+	type release struct {
+		Version string
+		Changes map[string]int
+	}
+	data := release{Version: "1.24", Changes: map[string]int{"runtime": 3, "crypto": 5, "os": 2}}
+	out, err := RenderTemplate(`Go {{.Version}} touches {{join (sortedKeys .Changes) ", "}}`, data)
+	if err != nil {
+		return fmt.Errorf("RenderTemplate: %w", err)
+	}
+	fmt.Fprintln(w, "RenderTemplate output:", out)
+
+	// An unclosed action fails to parse; a missing field fails to execute.
+	for _, text := range []string{`Go {{.Version}`, `Go {{.Codename}}`} {
+		if _, err := RenderTemplate(text, data); err != nil {
+			fmt.Fprintln(w, "RenderTemplate failed as expected:", err)
+		} else {
+			return fmt.Errorf("RenderTemplate(%q) unexpectedly succeeded", text)
+		}
 	}
-	fmt.Println("Template output:", tplOutput.String())
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 12. math/big: Encoding TextAppender
 //
 // big.Int now implements encoding.TextAppender.
-func DemoMathBigEncoding() {
+func DemoMathBigEncoding(w io.Writer) error {
 	bigInt := new(big.Int)
 	bigInt.SetString("12345678901234567890", 10)
-	var buf []byte
-	if appender, ok := interface{}(bigInt).(interface {
-		AppendText([]byte) []byte
-	}); ok {
-		buf = appender.AppendText(buf)
-	} else {
-		buf = []byte(bigInt.String())
+	buf, err := AppendTextOf(bigInt, nil)
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
 	}
-	fmt.Println("big.Int appended text:", string(buf))
+	fmt.Fprintln(w, "big.Int appended text:", string(buf))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
-// 13. math/rand: Using a Rand Instance
+// 13. math/rand and math/rand/v2: Using a Rand Instance
 //
 // The top-level Seed function is deprecated. Create a new Rand instance.
-func DemoMathRand() {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	fmt.Println("Random number (rand.New):", r.Int())
+// A Rand built from a fixed seed produces the same sequence on every run,
+// which makes randomized output reproducible. New code should prefer
+// math/rand/v2: its top-level functions are seeded randomly with no way to
+// reseed them, and reproducible streams come from an explicit source such as
+// PCG or ChaCha8.
+
+// RandomInts returns count pseudo-random ints in [0, 100) generated from
+// seed. The same seed always yields the same slice.
+func RandomInts(seed int64, count int) []int {
+	r := rand.New(rand.NewSource(seed))
+	out := make([]int, count)
+	for i := range out {
+		out[i] = r.Intn(100)
+	}
+	return out
+}
+
+// ShuffleDeterministic shuffles s in place using a PCG source seeded with
+// seed, so a given seed always produces the same permutation.
+func ShuffleDeterministic[T any](s []T, seed uint64) {
+	r := randv2.New(randv2.NewPCG(seed, seed))
+	r.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+func DemoMathRand(w io.Writer) error {
+	fmt.Fprintln(w, "Random number (math/rand/v2 IntN):", randv2.IntN(1000))
+
+	first, second := RandomInts(42, 5), RandomInts(42, 5)
+	if !slices.Equal(first, second) {
+		return fmt.Errorf("RandomInts(42, 5) gave %v then %v", first, second)
+	}
+	fmt.Fprintln(w, "RandomInts(42, 5):", first, "(same on a second call)")
+
+	pcg := randv2.New(randv2.NewPCG(1, 2))
+	fmt.Fprintln(w, "PCG(1, 2) IntN(100) x3:", pcg.IntN(100), pcg.IntN(100), pcg.IntN(100))
+
+	deck := []string{"A", "K", "Q", "J", "10", "9"}
+	a, b := slices.Clone(deck), slices.Clone(deck)
+	ShuffleDeterministic(a, 7)
+	ShuffleDeterministic(b, 7)
+	if !slices.Equal(a, b) {
+		return fmt.Errorf("ShuffleDeterministic(seed 7) gave %v then %v", a, b)
+	}
+	fmt.Fprintln(w, "ShuffleDeterministic(seed 7):", a, "(same permutation twice)")
+	c := slices.Clone(deck)
+	ShuffleDeterministic(c, 8)
+	fmt.Fprintln(w, "ShuffleDeterministic(seed 8):", c)
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 14. sync.Map Improvements
 //
-// The new sync.Map implementation now exhibits reduced contention.
-func DemoSyncMap() {
+// The new sync.Map implementation now exhibits reduced contention: it is
+// built on a concurrent hash-trie, so goroutines touching different keys
+// rarely contend with each other.
+
+// ConcurrentCounter starts workers goroutines that each increment a shared
+// set of counters incrementsPerWorker times, spreading the increments over
+// a few keys. Counters are created with LoadOrStore, so only one goroutine's
+// counter is kept per key, and incremented atomically. It returns a snapshot
+// of the final counts.
+func ConcurrentCounter(workers, incrementsPerWorker int) map[string]int {
+	const keys = 8
+	var counters sync.Map // string -> *atomic.Int64
+	fns := make([]func(), workers)
+	for i := range fns {
+		fns[i] = func() {
+			for j := range incrementsPerWorker {
+				key := fmt.Sprintf("key-%d", (i+j)%keys)
+				c, _ := counters.LoadOrStore(key, new(atomic.Int64))
+				c.(*atomic.Int64).Add(1)
+			}
+		}
+	}
+	goAll(fns...)
+
+	snapshot := make(map[string]int)
+	counters.Range(func(key, value any) bool {
+		snapshot[key.(string)] = int(value.(*atomic.Int64).Load())
+		return true
+	})
+	return snapshot
+}
+
+// DeleteMatching deletes every entry of m for which pred returns true and
+// reports how many it removed. Deleting from inside Range is safe: Range does
+// not hold a lock while calling its callback.
+func DeleteMatching(m *sync.Map, pred func(key, value any) bool) int {
+	deleted := 0
+	m.Range(func(key, value any) bool {
+		if pred(key, value) {
+			if _, loaded := m.LoadAndDelete(key); loaded {
+				deleted++
+			}
+		}
+		return true
+	})
+	return deleted
+}
+
+func DemoSyncMap(w io.Writer) error {
 	var m sync.Map
 	m.Store("key1", 100)
 	m.Store("key2", 200)
-	fmt.Println("Iterating over sync.Map:")
+	fmt.Fprintln(w, "Iterating over sync.Map:")
 	m.Range(func(key, value any) bool {
-		fmt.Printf("  key=%v, value=%v\n", key, value)
+		fmt.Fprintf(w, "  key=%v, value=%v\n", key, value)
 		return true
 	})
+
+	const workers, increments = 50, 1000
+	counts := ConcurrentCounter(workers, increments)
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total != workers*increments {
+		return fmt.Errorf("ConcurrentCounter counted %d increments, want %d", total, workers*increments)
+	}
+	fmt.Fprintf(w, "ConcurrentCounter: %d workers x %d increments over %d keys = %d\n", workers, increments, len(counts), total)
+
+	var numbers sync.Map
+	for i := range 100 {
+		numbers.Store(fmt.Sprint("n", i), i)
+	}
+	deleted := DeleteMatching(&numbers, func(_, value any) bool { return value.(int)%2 == 0 })
+	survivors := 0
+	var bad []int
+	numbers.Range(func(_, value any) bool {
+		survivors++
+		if value.(int)%2 == 0 {
+			bad = append(bad, value.(int))
+		}
+		return true
+	})
+	if deleted != 50 || survivors != 50 || len(bad) > 0 {
+		return fmt.Errorf("DeleteMatching: deleted %d, %d survivors, even survivors %v", deleted, survivors, bad)
+	}
+	fmt.Fprintf(w, "DeleteMatching removed %d even values; %d odd values remain\n", deleted, survivors)
+	return nil
 }
 
 // ----------------------------------------------------------------------------
@@ -325,8 +1078,9 @@ func DemoSyncMap() {
 //
 // In Go 1.24, the new log/slog package provides a DiscardHandler that discards log output.
 // For simplicity we just note its existence.
-func DemoSlog() {
-	fmt.Println("slog.DiscardHandler demo: In production, a DiscardHandler would discard logs.")
+func DemoSlog(w io.Writer) error {
+	fmt.Fprintln(w, "slog.DiscardHandler demo: In production, a DiscardHandler would discard logs.")
+	return nil
 }
 
 // ----------------------------------------------------------------------------
@@ -335,17 +1089,14 @@ func DemoSlog() {
 // 17. time: Encoding Interfaces
 //
 // time.Time now implements encoding.TextAppender.
-func DemoTimeEncoding() {
+func DemoTimeEncoding(w io.Writer) error {
 	now := time.Now()
-	var buf []byte
-	if appender, ok := interface{}(now).(interface {
-		AppendText([]byte) []byte
-	}); ok {
-		buf = appender.AppendText(buf)
-	} else {
-		buf = []byte(now.String())
+	buf, err := AppendTextOf(now, nil)
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
 	}
-	fmt.Println("time.Time appended text:", string(buf))
+	fmt.Fprintln(w, "time.Time appended text:", string(buf))
+	return nil
 }
 
 // ----------------------------------------------------------------------------
@@ -353,8 +1104,9 @@ func DemoTimeEncoding() {
 //
 // The new experimental testing/synctest package is best used in tests and requires
 // GOEXPERIMENT=synctest. Here we simply print a note.
-func DemoSynctest() {
-	fmt.Println("Experimental synctest demo: See tests built with GOEXPERIMENT=synctest for usage.")
+func DemoSynctest(w io.Writer) error {
+	fmt.Fprintln(w, "Experimental synctest demo: See tests built with GOEXPERIMENT=synctest for usage.")
+	return nil
 }
 
 // ----------------------------------------------------------------------------
@@ -362,43 +1114,4678 @@ func DemoSynctest() {
 //
 // Improvements to go/types now let you iterate over sequences with methods like Variables().
 // We simply note this improvement.
-func DemoGoTypesIterators() {
-	fmt.Println("go/types iterator demonstration: Use the Variables() method on tuples, etc.")
+func DemoGoTypesIterators(w io.Writer) error {
+	fmt.Fprintln(w, "go/types iterator demonstration: Use the Variables() method on tuples, etc.")
+	return nil
 }
 
 // ----------------------------------------------------------------------------
 // 20. maphash: Comparable and WriteComparable
 //
 // The new maphash functions make it easy to hash comparable values.
-func DemoMaphashComparable() {
+func DemoMaphashComparable(w io.Writer) error {
 	var h maphash.Hash
 	key := "myKey"
 	h.WriteString(key)
 	hashValue := h.Sum64()
-	fmt.Printf("Hash for key %q: %d\n", key, hashValue)
+	fmt.Fprintf(w, "Hash for key %q: %d\n", key, hashValue)
+	return nil
 }
 
-func main() {
-	fmt.Println("=== Go 1.24 Demo ===")
-	demoGenericTypeAlias()
-	fmt.Println("CGO Improvements Demo: Not implemented")
-	DemoFinalizers()
-	DemoCryptoPackages()
-	DemoDirectoryLimitedFS()
-	DemoBytesAndStringsIterators()
-	DemoEncodingAppend()
-	DemoNetipEncoding()
-	DemoRegexpEncoding()
-	DemoRuntimeGOROOT()
-	DemoTextTemplate()
-	DemoMathBigEncoding()
-	DemoMathRand()
-	DemoSyncMap()
-	DemoSlog()
-	fmt.Println("Text Template Range Demo: Not implemented") // #16
-	DemoTimeEncoding()
-	DemoSynctest()
-	DemoGoTypesIterators()
-	DemoMaphashComparable()
-	fmt.Println("=== Go 1.24 Demo End ===")
+// ----------------------------------------------------------------------------
+// 21. net/http: Client Retry with Context Deadline
+//
+// A resilient client retries transient server failures with exponential
+// backoff, and gives up once the deadline on the request context has passed.
+
+// newFlakyServer returns a test server that answers the first failures
+// requests with 503 Service Unavailable and every later request with 200 OK.
+func newFlakyServer(failures int) *httptest.Server {
+	var calls atomic.Int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= int64(failures) {
+			http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	}))
+}
+
+// getWithRetry issues GET requests to url until one does not fail with a
+// transport error or a 5xx status, doubling the delay between attempts. It
+// reports the number of attempts made, and stops retrying when ctx is done.
+func getWithRetry(ctx context.Context, client *http.Client, url string, backoff time.Duration) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, attempt, err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			if resp.StatusCode < http.StatusInternalServerError {
+				return resp, attempt, nil
+			}
+			resp.Body.Close()
+			err = fmt.Errorf("server returned %s", resp.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, attempt, fmt.Errorf("giving up: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+func DemoHTTPClientRetry(w io.Writer) error {
+	flaky := newFlakyServer(3)
+	defer flaky.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, attempts, err := getWithRetry(ctx, flaky.Client(), flaky.URL, 10*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("HTTP retry: %w", err)
+	}
+	resp.Body.Close()
+	fmt.Fprintf(w, "HTTP retry succeeded with %q after %d attempts\n", resp.Status, attempts)
+
+	// A server that never recovers keeps failing until the deadline passes.
+	down := newFlakyServer(1 << 30)
+	defer down.Close()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, attempts, err = getWithRetry(ctx, down.Client(), down.URL, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("HTTP retry against a down server: want a deadline error, got %v", err)
+	}
+	fmt.Fprintf(w, "HTTP retry stopped after %d attempts: %v\n", attempts, err)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 22. go/doc/comment: Rendering Doc Comments
+//
+// The go/doc/comment package parses doc comment text into a syntax tree that
+// a Printer can render as Markdown, HTML, or plain text.
+func DemoDocComment(w io.Writer) error {
+	const text = `Package greet says hello.
+
+Use [Hello] to build a greeting, for example:
+
+	msg := greet.Hello("gopher")
+
+See the [Go website] for more.
+
+[Go website]: https://go.dev
+`
+	// LookupSym reports which identifiers exist, so [Hello] becomes a doc link.
+	p := comment.Parser{
+		LookupSym: func(recv, name string) bool { return recv == "" && name == "Hello" },
+	}
+	doc := p.Parse(text)
+
+	var pr comment.Printer
+	fmt.Fprintln(w, "Doc comment as Markdown:")
+	fmt.Fprint(w, string(pr.Markdown(doc)))
+	fmt.Fprintln(w, "Doc comment as text:")
+	fmt.Fprint(w, string(pr.Text(doc)))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 23. maphash: Hashing Slices
+//
+// Slices are not comparable, so maphash.Comparable(seed, []byte{...}) does not
+// compile. Hash a byte slice's contents with maphash.Bytes instead, or copy a
+// fixed-size slice into an array, which is comparable, and hash a struct
+// holding it.
+
+// hashNilAware hashes b like maphash.Bytes, but writes a leading marker byte
+// so that a nil slice and an empty slice hash differently.
+func hashNilAware(seed maphash.Seed, b []byte) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	if b == nil {
+		h.WriteByte(0)
+	} else {
+		h.WriteByte(1)
+		h.Write(b)
+	}
+	return h.Sum64()
+}
+
+func DemoHashSlice(w io.Writer) error {
+	seed := maphash.MakeSeed()
+
+	data := []byte("hello")
+	fmt.Fprintf(w, "maphash.Bytes(%q): %d\n", data, maphash.Bytes(seed, data))
+
+	// A slice of known length converts to an array, and a struct of
+	// comparable fields can be hashed with maphash.Comparable.
+	type point3 struct {
+		Label  string
+		Coords [3]int
+	}
+	coords := []int{1, 2, 3}
+	key := point3{Label: "p", Coords: [3]int(coords)}
+	fmt.Fprintf(w, "maphash.Comparable(%v): %d\n", key, maphash.Comparable(seed, key))
+
+	// maphash.Bytes only sees the contents, so nil and empty slices collide.
+	var nilSlice []byte
+	emptySlice := []byte{}
+	fmt.Fprintln(w, "nil and empty slices hash equal with maphash.Bytes:",
+		maphash.Bytes(seed, nilSlice) == maphash.Bytes(seed, emptySlice))
+	fmt.Fprintln(w, "nil and empty slices hash equal with a nil marker:",
+		hashNilAware(seed, nilSlice) == hashNilAware(seed, emptySlice))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 24. crypto/tls: Exporting Keying Material
+//
+// After a TLS 1.3 handshake both peers can derive identical keying material
+// from the session with ConnectionState.ExportKeyingMaterial (RFC 5705). This
+// is how protocols bind application-level credentials to a TLS channel.
+
+// tlsHandshake connects a client and a server over an in-memory net.Pipe and
+// completes the TLS handshake on both ends. On failure it returns the errors
+// reported by both sides.
+//
+// net.Pipe has no buffering, so a side that aborts the handshake can block
+// writing its alert while the peer is still writing its own flight. A short
+// deadline on both ends breaks that deadlock; it is cleared on success.
+func tlsHandshake(serverConf, clientConf *tls.Config) (client, server *tls.Conn, err error) {
+	c, s := net.Pipe()
+	deadline := time.Now().Add(time.Second)
+	c.SetDeadline(deadline)
+	s.SetDeadline(deadline)
+	client, server = tls.Client(c, clientConf), tls.Server(s, serverConf)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		err := server.Handshake()
+		if err != nil {
+			// Unblock the client if the server rejected the handshake.
+			s.Close()
+		}
+		serverErr <- err
+	}()
+	clientErr := client.Handshake()
+	if clientErr != nil {
+		c.Close()
+	}
+	if err := errors.Join(clientErr, <-serverErr); err != nil {
+		client.Close()
+		server.Close()
+		return nil, nil, err
+	}
+	c.SetDeadline(time.Time{})
+	s.SetDeadline(time.Time{})
+	return client, server, nil
+}
+
+// closePipe closes both ends of a connection made by tlsHandshake. Closing
+// a tls.Conn first writes a close_notify alert, which on the unbuffered pipe
+// blocks until its five-second timeout unless the peer is reading, so the
+// underlying pipe is closed instead.
+func closePipe(client, server *tls.Conn) {
+	client.NetConn().Close()
+	server.NetConn().Close()
+}
+
+func DemoKeyingMaterial(w io.Writer) error {
+	serverConf, clientConf, err := newInMemoryTLS("demo.test")
+	if err != nil {
+		return err
+	}
+	serverConf.MinVersion = tls.VersionTLS13
+	clientConf.MinVersion = tls.VersionTLS13
+	client, server, err := tlsHandshake(serverConf, clientConf)
+	if err != nil {
+		return fmt.Errorf("TLS handshake: %w", err)
+	}
+	defer closePipe(client, server)
+
+	const label = "EXPORTER-go124-demo"
+	clientState, serverState := client.ConnectionState(), server.ConnectionState()
+	clientKM, err := clientState.ExportKeyingMaterial(label, nil, 32)
+	if err != nil {
+		return fmt.Errorf("client export: %w", err)
+	}
+	serverKM, err := serverState.ExportKeyingMaterial(label, nil, 32)
+	if err != nil {
+		return fmt.Errorf("server export: %w", err)
+	}
+	if !bytes.Equal(clientKM, serverKM) {
+		return fmt.Errorf("exported keying material differs: client %x, server %x", clientKM, serverKM)
+	}
+	fmt.Fprintf(w, "Exported %d bytes of keying material, identical on both sides\n", len(clientKM))
+
+	// A different label derives unrelated material from the same session.
+	otherKM, err := serverState.ExportKeyingMaterial("EXPORTER-other-label", nil, 32)
+	if err != nil {
+		return fmt.Errorf("server export: %w", err)
+	}
+	if bytes.Equal(clientKM, otherKM) {
+		return errors.New("keying material for a mismatched label matched")
+	}
+	fmt.Fprintln(w, "Keying material for a mismatched label differs")
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 25. Generics with math/bits: Bit Manipulation Helpers
+//
+// A type constraint over the unsigned integer types lets one implementation
+// of these helpers, built on math/bits, serve uint8 through uint64.
+
+// Unsigned is satisfied by every unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// bitWidth returns the size of T in bits.
+func bitWidth[T Unsigned]() int {
+	return bits.Len64(uint64(^T(0)))
+}
+
+// PopCount returns the number of one bits in x.
+func PopCount[T Unsigned](x T) int {
+	return bits.OnesCount64(uint64(x))
+}
+
+// LeadingZeros returns the number of leading zero bits in x, counted within
+// the width of T. It returns the full width of T when x is zero.
+func LeadingZeros[T Unsigned](x T) int {
+	return bits.LeadingZeros64(uint64(x)) - (64 - bitWidth[T]())
+}
+
+// BitSet is a set of small integers stored in the bits of a single T. It can
+// hold the values 0 through the width of T minus one.
+type BitSet[T Unsigned] struct {
+	bits T
+}
+
+// Set adds i to the set. Values that do not fit in T are ignored.
+func (s *BitSet[T]) Set(i int) {
+	if i >= 0 && i < bitWidth[T]() {
+		s.bits |= 1 << i
+	}
+}
+
+// Clear removes i from the set.
+func (s *BitSet[T]) Clear(i int) {
+	if i >= 0 && i < bitWidth[T]() {
+		s.bits &^= 1 << i
+	}
+}
+
+// Has reports whether i is in the set.
+func (s BitSet[T]) Has(i int) bool {
+	return i >= 0 && i < bitWidth[T]() && s.bits&(1<<i) != 0
+}
+
+// Len returns the number of values in the set.
+func (s BitSet[T]) Len() int {
+	return PopCount(s.bits)
+}
+
+func DemoBits(w io.Writer) error {
+	fmt.Fprintf(w, "uint8(0b1011_0000): PopCount=%d LeadingZeros=%d\n",
+		PopCount(uint8(0b1011_0000)), LeadingZeros(uint8(0b1011_0000)))
+	fmt.Fprintf(w, "uint32(1<<20): PopCount=%d LeadingZeros=%d\n",
+		PopCount(uint32(1<<20)), LeadingZeros(uint32(1<<20)))
+	fmt.Fprintf(w, "uint64(max): PopCount=%d LeadingZeros=%d\n",
+		PopCount(uint64(math.MaxUint64)), LeadingZeros(uint64(math.MaxUint64)))
+	// Zero has no set bits, and all of its bits are leading zeros.
+	fmt.Fprintf(w, "uint8(0): PopCount=%d LeadingZeros=%d\n", PopCount(uint8(0)), LeadingZeros(uint8(0)))
+	fmt.Fprintf(w, "uint64(0): PopCount=%d LeadingZeros=%d\n", PopCount(uint64(0)), LeadingZeros(uint64(0)))
+
+	var set BitSet[uint8]
+	for _, i := range []int{1, 3, 5, 7, 9} {
+		set.Set(i)
+	}
+	set.Clear(3)
+	fmt.Fprintf(w, "BitSet[uint8]: bits=%08b len=%d has(5)=%t has(3)=%t has(9)=%t\n",
+		set.bits, set.Len(), set.Has(5), set.Has(3), set.Has(9))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 26. net/http: Request.PathValue Wildcards
+//
+// Since Go 1.22, ServeMux patterns may contain named wildcards, and handlers
+// read the matched segments with Request.PathValue. A trailing {name...}
+// wildcard matches the rest of the path.
+func DemoPathValue(w io.Writer) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}/posts/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "id=%s slug=%s", r.PathValue("id"), r.PathValue("slug"))
+	})
+	mux.HandleFunc("GET /files/{rest...}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "rest=%s", r.PathValue("rest"))
+	})
+
+	for _, path := range []string{"/users/42/posts/hello-go", "/files/docs/2025/notes.txt"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		fmt.Fprintf(w, "PathValue for %s: %s\n", path, rec.Body.String())
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 27. time: Named Layout Constants
+//
+// time.DateTime, time.DateOnly, and time.TimeOnly (added in Go 1.20) name the
+// layouts most programs spell out by hand.
+func DemoTimeLayouts(w io.Writer) error {
+	instant := time.Date(2025, time.February, 11, 14, 30, 15, 123456789, time.UTC)
+	layouts := []struct {
+		name, layout string
+	}{
+		{"DateTime", time.DateTime},
+		{"DateOnly", time.DateOnly},
+		{"TimeOnly", time.TimeOnly},
+		{"RFC3339Nano", time.RFC3339Nano},
+	}
+	for _, l := range layouts {
+		formatted := instant.Format(l.layout)
+		parsed, err := time.Parse(l.layout, formatted)
+		if err != nil {
+			return fmt.Errorf("parsing time: %w", err)
+		}
+		fmt.Fprintf(w, "%-11s %-30s parsed back: %v\n", l.name, formatted, parsed)
+	}
+
+	// DateOnly carries no time of day, so parsing yields midnight UTC.
+	day, err := time.Parse(time.DateOnly, instant.Format(time.DateOnly))
+	if err != nil {
+		return fmt.Errorf("parsing date: %w", err)
+	}
+	midnight := time.Date(instant.Year(), instant.Month(), instant.Day(), 0, 0, 0, 0, time.UTC)
+	fmt.Fprintln(w, "DateOnly round trip is midnight of the same day:", day.Equal(midnight))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 28. Iterators, Generics, and Channels: A Generic Event Bus
+//
+// Bus[T] fans published events out to subscribers. Each subscription is an
+// iter.Seq[T], so consumers simply range over it, and breaking out of the
+// loop unsubscribes.
+
+// Bus delivers each published event to every current subscriber. The zero
+// value is not usable; create buses with NewBus.
+type Bus[T any] struct {
+	mu     sync.Mutex
+	subs   map[*subscription[T]]struct{}
+	closed bool
+}
+
+type subscription[T any] struct {
+	events chan T
+	done   chan struct{} // closed when the subscriber stops ranging
+	stop   sync.Once
+}
+
+// NewBus returns an empty bus.
+func NewBus[T any]() *Bus[T] {
+	return &Bus[T]{subs: make(map[*subscription[T]]struct{})}
+}
+
+// Subscribe registers a subscriber and returns the sequence of events
+// published from now on. The sequence ends when the bus is closed. Breaking
+// out of the loop removes the subscription; a sequence that is never ranged
+// over stays subscribed until the bus is closed.
+func (b *Bus[T]) Subscribe() iter.Seq[T] {
+	sub := &subscription[T]{events: make(chan T, 16), done: make(chan struct{})}
+	b.mu.Lock()
+	if b.closed {
+		close(sub.events)
+	} else {
+		b.subs[sub] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	return func(yield func(T) bool) {
+		defer b.unsubscribe(sub)
+		for v := range sub.events {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (b *Bus[T]) unsubscribe(sub *subscription[T]) {
+	sub.stop.Do(func() {
+		// Closing done first releases a Publish blocked on this subscriber.
+		close(sub.done)
+		b.mu.Lock()
+		delete(b.subs, sub)
+		b.mu.Unlock()
+	})
+}
+
+// Publish delivers v to every subscriber, waiting for room in each
+// subscriber's buffer. Publishing on a closed bus does nothing.
+func (b *Bus[T]) Publish(v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.events <- v:
+		case <-sub.done:
+		}
+	}
+}
+
+// Close ends every subscription after its buffered events are consumed.
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		close(sub.events)
+		delete(b.subs, sub)
+	}
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus[T]) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+func DemoEventBus(w io.Writer) error {
+	bus := NewBus[string]()
+
+	var all, firstTwo []string
+	allEvents, someEvents := bus.Subscribe(), bus.Subscribe()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range allEvents {
+			all = append(all, e)
+		}
+	}()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for e := range someEvents {
+			firstTwo = append(firstTwo, e)
+			if len(firstTwo) == 2 {
+				break
+			}
+		}
+	}()
+
+	bus.Publish("started")
+	bus.Publish("progress")
+	<-stopped
+	fmt.Fprintln(w, "Subscribers after one broke out of its loop:", bus.Subscribers())
+	bus.Publish("progress")
+	bus.Publish("finished")
+	bus.Close()
+	wg.Wait()
+
+	fmt.Fprintln(w, "Subscriber 1 received:", all)
+	fmt.Fprintln(w, "Subscriber 2 received:", firstTwo)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 29. crypto/mlkem: Post-Quantum Key Encapsulation
+//
+// Go 1.24 adds ML-KEM (FIPS 203). A decapsulation key can be derived
+// deterministically from a 64-byte seed, which makes the key pair
+// reproducible, while each encapsulation uses fresh randomness.
+
+// mlkemDemoSeed is the fixed seed for the demo key pair: the bytes 0x00
+// through 0x3f in order. It is a test value only and must never be used for
+// real keys.
+var mlkemDemoSeed = func() []byte {
+	seed := make([]byte, mlkem.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	return seed
+}()
+
+// mlkemDemoKeyDigest is the recorded SHA3-256 digest of the ML-KEM-768
+// encapsulation key derived from mlkemDemoSeed. A mismatch means key
+// generation changed.
+const mlkemDemoKeyDigest = "a24e16d8f8f9383a95b77050f4d9fd2f5733eec1d63ef3c23ebf9918173669a7"
+
+func DemoMLKEM(w io.Writer) error {
+	dk, err := mlkem.NewDecapsulationKey768(mlkemDemoSeed)
+	if err != nil {
+		return fmt.Errorf("ML-KEM key: %w", err)
+	}
+	ek := dk.EncapsulationKey()
+	digest := sha3.Sum256(ek.Bytes())
+	if got := hex.EncodeToString(digest[:]); got != mlkemDemoKeyDigest {
+		return fmt.Errorf("ML-KEM-768 encapsulation key digest is %s, want %s", got, mlkemDemoKeyDigest)
+	}
+	fmt.Fprintln(w, "ML-KEM-768 encapsulation key from fixed seed matches recorded digest")
+
+	sharedKey, ciphertext := ek.Encapsulate()
+	recovered, err := dk.Decapsulate(ciphertext)
+	if err != nil {
+		return fmt.Errorf("ML-KEM decapsulation: %w", err)
+	}
+	if !bytes.Equal(sharedKey, recovered) {
+		return errors.New("ML-KEM decapsulated shared key does not match")
+	}
+	fmt.Fprintf(w, "ML-KEM-768 ciphertext is %d bytes; decapsulated shared key matches\n", len(ciphertext))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 30. io/fs: Stat and ModTime Through os.Root.FS
+//
+// Root.FS exposes a sandboxed directory as an fs.FS, so generic io/fs helpers
+// like fs.Stat and fs.ReadDir work on it. Here a listing is sorted by
+// modification time with slices.SortFunc and time.Time.Compare.
+func DemoFSStat(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-fsstat")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := time.Date(2025, time.February, 11, 12, 0, 0, 0, time.UTC)
+	files := []struct {
+		name    string
+		content string
+		modTime time.Time
+	}{
+		{"newest.txt", "written last", base.Add(2 * time.Hour)},
+		{"oldest.txt", "written first", base},
+		// b.txt and a.txt share a modification time; the name breaks the tie.
+		{"b.txt", "tie", base.Add(time.Hour)},
+		{"a.txt", "tie", base.Add(time.Hour)},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			return fmt.Errorf("writing file: %w", err)
+		}
+		if err := os.Chtimes(path, f.modTime, f.modTime); err != nil {
+			return fmt.Errorf("setting times: %w", err)
+		}
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+	fsys := root.FS()
+
+	info, err := fs.Stat(fsys, "oldest.txt")
+	if err != nil {
+		return fmt.Errorf("stating file: %w", err)
+	}
+	fmt.Fprintf(w, "fs.Stat oldest.txt: size=%d modTime=%s\n", info.Size(), info.ModTime().UTC().Format(time.DateTime))
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("reading directory: %w", err)
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return fmt.Errorf("reading file info: %w", err)
+		}
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b fs.FileInfo) int {
+		if c := a.ModTime().Compare(b.ModTime()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name(), b.Name())
+	})
+	fmt.Fprintln(w, "Files sorted by modification time:")
+	for _, info := range infos {
+		fmt.Fprintf(w, " - %-10s %2d bytes  %s\n", info.Name(), info.Size(), info.ModTime().UTC().Format(time.DateTime))
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 31. sync.Map and sync.OnceValue: Race-Free Lazy Initialization
+//
+// LoadOrStore alone can run an expensive constructor several times when
+// goroutines race on a new key. Storing a sync.OnceValue wrapper instead
+// means only the stored wrapper is ever called, so each value is built once.
+
+// lazyLoad returns the value for key in m, calling newValue to construct it if
+// this is the first request for key. Concurrent callers for the same key wait
+// for, and share, a single call of newValue.
+func lazyLoad[K comparable, V any](m *sync.Map, key K, newValue func(K) V) V {
+	f, ok := m.Load(key)
+	if !ok {
+		f, _ = m.LoadOrStore(key, sync.OnceValue(func() V { return newValue(key) }))
+	}
+	return f.(func() V)()
+}
+
+func DemoLazyInit(w io.Writer) error {
+	var (
+		cache sync.Map
+		mu    sync.Mutex
+		calls = make(map[string]int)
+	)
+	newConn := func(host string) string {
+		mu.Lock()
+		calls[host]++
+		mu.Unlock()
+		time.Sleep(time.Millisecond) // simulate an expensive dial
+		return "conn(" + host + ")"
+	}
+
+	hosts := []string{"db", "cache", "queue"}
+	var wg sync.WaitGroup
+	for range 20 {
+		for _, host := range hosts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lazyLoad(&cache, host, newConn)
+			}()
+		}
+	}
+	wg.Wait()
+
+	fmt.Fprintln(w, "Lazy init value for db:", lazyLoad(&cache, "db", newConn))
+	for _, host := range hosts {
+		if calls[host] != 1 {
+			return fmt.Errorf("constructor ran %d times for %s, want 1", calls[host], host)
+		}
+		fmt.Fprintf(w, "Constructor calls for %s after 20 concurrent loads: %d\n", host, calls[host])
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 32. encoding/json: Preserving Large Numbers with json.Number
+//
+// Decoding into interface{} turns every JSON number into a float64, which only
+// holds integers exactly up to 2^53. Decoder.UseNumber keeps the literal text
+// as a json.Number, which can then be parsed without loss, e.g. into big.Int.
+func DemoJSONNumber(w io.Writer) error {
+	// 2^53 + 1 is the smallest positive integer a float64 cannot represent.
+	const input = `{"id": 9007199254740993, "balance": 123456789012345678901234567890}`
+
+	var lossy map[string]any
+	if err := json.Unmarshal([]byte(input), &lossy); err != nil {
+		return fmt.Errorf("JSON decode: %w", err)
+	}
+	fmt.Fprintf(w, "Default decoding: id=%.0f balance=%.0f\n", lossy["id"], lossy["balance"])
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+	var exact map[string]any
+	if err := dec.Decode(&exact); err != nil {
+		return fmt.Errorf("JSON decode: %w", err)
+	}
+	for _, key := range []string{"id", "balance"} {
+		num := exact[key].(json.Number)
+		n, ok := new(big.Int).SetString(num.String(), 10)
+		if !ok {
+			return fmt.Errorf("invalid integer: %v", num)
+		}
+		fmt.Fprintf(w, "UseNumber decoding: %s=%s (as big.Int: %v)\n", key, num, n)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 33. math/rand/v2: Retry with Exponential Backoff and Jitter
+//
+// Retry wraps any fallible operation. Random jitter from math/rand/v2 spreads
+// out retries from many clients so they do not hit a server in lockstep.
+
+// retryBaseDelay is the delay Retry waits after the first failed attempt,
+// and retryMaxDelay caps the delay as it doubles.
+const (
+	retryBaseDelay = 5 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// Retry calls op until it succeeds, at most attempts times, and returns the
+// first successful result. After each failure it waits for an exponentially
+// growing delay, capped at retryMaxDelay, plus up to the same amount of
+// random jitter. If every attempt fails, Retry returns the last error; if ctx
+// is done while waiting, it returns the last error joined with ctx.Err().
+func Retry[T any](ctx context.Context, attempts int, op func() (T, error)) (T, error) {
+	var zero T
+	if attempts < 1 {
+		return zero, fmt.Errorf("retry: attempts must be positive, got %d", attempts)
+	}
+	delay := retryBaseDelay
+	for i := 1; ; i++ {
+		v, err := op()
+		if err == nil {
+			return v, nil
+		}
+		if i == attempts {
+			return zero, err
+		}
+		jitter := time.Duration(randv2.Int64N(int64(delay)))
+		select {
+		case <-ctx.Done():
+			return zero, errors.Join(err, ctx.Err())
+		case <-time.After(delay + jitter):
+		}
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
+
+func DemoRetry(w io.Writer) error {
+	ctx := context.Background()
+	errUnavailable := errors.New("service unavailable")
+
+	calls := 0
+	v, err := Retry(ctx, 5, func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errUnavailable
+		}
+		return "payload", nil
+	})
+	if err != nil || v != "payload" || calls != 3 {
+		return fmt.Errorf("Retry flaky operation: got %q, %v after %d calls; want \"payload\" after 3", v, err, calls)
+	}
+	fmt.Fprintf(w, "Retry flaky operation: %q, err=%v, calls=%d\n", v, err, calls)
+
+	calls = 0
+	n, err := Retry(ctx, 5, func() (int, error) {
+		calls++
+		return 42, nil
+	})
+	if err != nil || n != 42 || calls != 1 {
+		return fmt.Errorf("Retry immediate success: got %d, %v after %d calls; want 42 after 1", n, err, calls)
+	}
+	fmt.Fprintf(w, "Retry immediate success: %d, err=%v, calls=%d\n", n, err, calls)
+
+	calls = 0
+	_, err = Retry(ctx, 3, func() (int, error) {
+		calls++
+		return 0, errUnavailable
+	})
+	if !errors.Is(err, errUnavailable) || calls != 3 {
+		return fmt.Errorf("Retry total failure: got %v after %d calls; want %v after 3", err, calls, errUnavailable)
+	}
+	fmt.Fprintf(w, "Retry total failure: err=%v, calls=%d\n", err, calls)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = Retry(timeoutCtx, 100, func() (int, error) {
+		return 0, errUnavailable
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errUnavailable) {
+		return fmt.Errorf("Retry canceled: want the deadline and the last error joined, got %v", err)
+	}
+	fmt.Fprintln(w, "Retry canceled: deadline exceeded and last error kept:", err)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 34. go/types: Type Checking with an Importer
+//
+// A types.Config with an Importer resolves imported packages, so the checker
+// can type-check code that uses other packages' symbols. importer.ForCompiler
+// reads the export data the gc compiler produces for those packages.
+
+// typeCheck parses src as a file named name and type-checks it, resolving
+// imports with the gc importer.
+func typeCheck(name, src string) (*types.Package, *types.Info, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "gc", nil)}
+	info := &types.Info{Uses: make(map[*ast.Ident]types.Object)}
+	pkg, err := conf.Check(file.Name.Name, fset, []*ast.File{file}, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg, info, nil
+}
+
+func DemoTypeChecker(w io.Writer) error {
+	const src = `package greet
+
+import "fmt"
+
+func Hello(name string) string { return fmt.Sprintf("hello, %s", name) }
+`
+	pkg, info, err := typeCheck("greet.go", src)
+	if err != nil {
+		return fmt.Errorf("type check: %w", err)
+	}
+	for id, obj := range info.Uses {
+		if fn, ok := obj.(*types.Func); ok && id.Name == "Sprintf" {
+			fmt.Fprintln(w, "Resolved external symbol:", fn)
+		}
+	}
+	for _, imp := range pkg.Imports() {
+		names := imp.Scope().Names()
+		fmt.Fprintf(w, "Package %q exports %d names, e.g. %v\n", imp.Path(), len(names), names[:min(5, len(names))])
+	}
+
+	const bad = `package broken
+
+import "example.com/does/not/exist"
+
+var _ = exist.Value
+`
+	if _, _, err := typeCheck("broken.go", bad); err != nil {
+		fmt.Fprintln(w, "Unresolvable import reported:", err)
+	} else {
+		return errors.New("type-checking an unresolvable import unexpectedly succeeded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 35. Streaming File Encryption: os.Root, crypto/hkdf, and AES-CTR
+//
+// This ties the filesystem and crypto demos together: a key derived with the
+// new crypto/hkdf package encrypts a file inside an os.Root, streaming it
+// through AES-CTR so the file never has to fit in memory. CTR mode provides
+// no integrity; real code should authenticate the ciphertext (e.g. AES-GCM).
+
+// encryptFile encrypts the file src within root into dst, writing a random
+// IV ahead of the ciphertext.
+func encryptFile(root *os.Root, src, dst string, key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	in, err := root.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := root.Create(dst)
+	if err != nil {
+		return err
+	}
+	iv := make([]byte, aes.BlockSize)
+	crand.Read(iv)
+	if _, err := out.Write(iv); err != nil {
+		out.Close()
+		return err
+	}
+	w := cipher.StreamWriter{S: cipher.NewCTR(block, iv), W: out}
+	if _, err := io.Copy(w, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// decryptFile reads a file written by encryptFile from root and returns the
+// plaintext.
+func decryptFile(root *os.Root, name string, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	in, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(in, iv); err != nil {
+		return nil, fmt.Errorf("reading IV: %w", err)
+	}
+	return io.ReadAll(cipher.StreamReader{S: cipher.NewCTR(block, iv), R: in})
+}
+
+func DemoEncryptFile(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-encrypt")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+
+	key, err := hkdf.Key(sha256.New, []byte("master secret"), []byte("demo salt"), "file encryption", 32)
+	if err != nil {
+		return fmt.Errorf("HKDF: %w", err)
+	}
+
+	files := map[string][]byte{
+		"message.txt": bytes.Repeat([]byte("attack at dawn\n"), 100),
+		"empty.txt":   nil,
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		f, err := root.Create(name)
+		if err != nil {
+			return fmt.Errorf("creating file: %w", err)
+		}
+		_, err = f.Write(files[name])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing file: %w", err)
+		}
+
+		if err := encryptFile(root, name, name+".enc", key); err != nil {
+			return fmt.Errorf("encryption: %w", err)
+		}
+		info, err := root.Stat(name + ".enc")
+		if err != nil {
+			return fmt.Errorf("stating file: %w", err)
+		}
+		plaintext, err := decryptFile(root, name+".enc", key)
+		if err != nil {
+			return fmt.Errorf("decryption: %w", err)
+		}
+		if !bytes.Equal(plaintext, files[name]) {
+			return fmt.Errorf("decrypting %s gave %q, want %q", name, plaintext, files[name])
+		}
+		fmt.Fprintf(w, "Encrypted %s: %d bytes plaintext, %d bytes on disk (IV + ciphertext), round trip ok\n",
+			name, len(files[name]), info.Size())
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 36. slices: Min, Max, MinFunc, and MaxFunc
+//
+// slices.Min and slices.Max work on any ordered element type; MinFunc and
+// MaxFunc take a comparison function for everything else. All four panic on
+// an empty slice, so guard the call when the input may be empty.
+func DemoSlicesMinMax(w io.Writer) error {
+	scores := []int{72, 95, 61, 88}
+	fmt.Fprintf(w, "slices.Min/Max of %v: %d, %d\n", scores, slices.Min(scores), slices.Max(scores))
+
+	type employee struct {
+		Name string
+		Age  int
+	}
+	staff := []employee{{"Alice", 34}, {"Bob", 27}, {"Carol", 45}}
+	byAge := func(a, b employee) int { return cmp.Compare(a.Age, b.Age) }
+	fmt.Fprintln(w, "Youngest (MinFunc):", slices.MinFunc(staff, byAge).Name)
+	fmt.Fprintln(w, "Oldest (MaxFunc):", slices.MaxFunc(staff, byAge).Name)
+
+	var none []int
+	if len(none) > 0 {
+		fmt.Fprintln(w, "Max of empty slice:", slices.Max(none))
+	} else {
+		fmt.Fprintln(w, "Empty slice: skipped slices.Max, which would panic")
+	}
+
+	// For floating-point slices, a NaN anywhere propagates to the result.
+	readings := []float64{1.5, math.NaN(), -2}
+	fmt.Fprintf(w, "slices.Min/Max of %v: %v, %v\n", readings, slices.Min(readings), slices.Max(readings))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 37. net/netip: Addresses as Map Keys
+//
+// net.IP is a byte slice, so it cannot be a map key without converting it to
+// a string first. netip.Addr is a comparable value type and works directly,
+// and Addr.Compare gives a total order with IPv4 before IPv6.
+func DemoNetipMapKey(w io.Writer) error {
+	log := []string{"192.0.2.10", "2001:db8::1", "192.0.2.2", "192.0.2.10", "2001:db8::1", "10.0.0.1", "192.0.2.10"}
+
+	counts := make(map[netip.Addr]int)
+	for _, s := range log {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return fmt.Errorf("parsing IP: %w", err)
+		}
+		counts[addr]++
+	}
+
+	fmt.Fprintln(w, "Requests per address:")
+	for _, addr := range slices.SortedFunc(maps.Keys(counts), netip.Addr.Compare) {
+		fmt.Fprintf(w, "  %-12s %d\n", addr, counts[addr])
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 38. strings: SplitSeq Against Split
+//
+// strings.SplitSeq yields the same pieces as strings.Split without building
+// the slice. TestSplitSeqProperty checks that with testing/quick over
+// generated inputs; the demo shows the edge cases: empty pieces, a missing
+// separator, and an empty separator, which splits into UTF-8 sequences.
+func DemoSplitSeq(w io.Writer) error {
+	cases := []struct{ s, sep string }{
+		{"a,b,c", ","},
+		{"a,,b,", ","},
+		{"no separator", ","},
+		{"", ","},
+		{"héllo", ""},
+	}
+	for _, c := range cases {
+		seq := slices.Collect(strings.SplitSeq(c.s, c.sep))
+		if want := strings.Split(c.s, c.sep); !slices.Equal(seq, want) {
+			return fmt.Errorf("SplitSeq(%q, %q) = %q, Split = %q", c.s, c.sep, seq, want)
+		}
+		fmt.Fprintf(w, "SplitSeq(%q, %q) = %q\n", c.s, c.sep, seq)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 39. crypto/tls: Minimum Version and Cipher Suite Policy
+//
+// Setting MinVersion to TLS 1.3 on a server rejects older clients outright.
+// TLS 1.3 cipher suites are not configurable, so the negotiated suite is
+// inspected rather than chosen.
+func DemoTLSPolicy(w io.Writer) error {
+	serverConf, clientConf, err := newInMemoryTLS("demo.test")
+	if err != nil {
+		return err
+	}
+	serverConf.MinVersion = tls.VersionTLS13
+
+	client, server, err := tlsHandshake(serverConf, clientConf)
+	if err != nil {
+		return fmt.Errorf("TLS handshake: %w", err)
+	}
+	state := client.ConnectionState()
+	fmt.Fprintf(w, "Negotiated %s with %s\n", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+	closePipe(client, server)
+
+	legacyClient := clientConf.Clone()
+	legacyClient.MaxVersion = tls.VersionTLS12
+	if _, _, err := tlsHandshake(serverConf, legacyClient); err != nil {
+		fmt.Fprintln(w, "TLS 1.2 client rejected by TLS 1.3-only server:", err)
+	} else {
+		return errors.New("TLS 1.2 client unexpectedly connected")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 40. Generic Type Aliases: Functional Options
+//
+// A generic alias Option[T] = func(*T) gives every functional option a
+// readable name without introducing a new type, so plain func literals and
+// named option constructors mix freely. Apply works for any config type.
+
+// Option configures a *T.
+type Option[T any] = func(*T)
+
+// Apply applies opts to v in order and returns v.
+func Apply[T any](v *T, opts ...Option[T]) *T {
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+type serverConfig struct {
+	Host    string
+	Port    int
+	Timeout time.Duration
+	Tags    []string
+}
+
+// newServerConfig returns a config with default values, modified by opts.
+func newServerConfig(opts ...Option[serverConfig]) *serverConfig {
+	return Apply(&serverConfig{Host: "localhost", Port: 8080, Timeout: 30 * time.Second}, opts...)
+}
+
+func withPort(port int) Option[serverConfig] {
+	return func(c *serverConfig) { c.Port = port }
+}
+
+func withTag(tag string) Option[serverConfig] {
+	return func(c *serverConfig) { c.Tags = append(c.Tags, tag) }
+}
+
+func DemoOptions(w io.Writer) error {
+	fmt.Fprintf(w, "Default config: %+v\n", *newServerConfig())
+
+	cfg := newServerConfig(
+		withPort(9443),
+		withTag("prod"),
+		withTag("eu-west"),
+		func(c *serverConfig) { c.Timeout = 5 * time.Second },
+	)
+	fmt.Fprintf(w, "Configured: %+v\n", *cfg)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 41. runtime/debug: Soft Memory Limit
+//
+// debug.SetMemoryLimit makes the garbage collector run more often as the Go
+// runtime's total memory use approaches the limit. With the GOGC-based
+// trigger turned off, collections happen only because of the limit, which
+// makes its effect easy to observe through runtime/metrics.
+
+// gcCycles returns the number of completed GC cycles.
+func gcCycles() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/cycles/total:gc-cycles"}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
+
+// runtimeMemory returns the total memory mapped by the Go runtime.
+func runtimeMemory() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/total:bytes"}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
+
+// churn allocates total bytes of short-lived garbage in 64 KiB chunks.
+func churn(total int) {
+	const chunk = 64 << 10
+	var sink []byte
+	for range total / chunk {
+		sink = make([]byte, chunk)
+		sink[0] = 1
+	}
+	runtime.KeepAlive(sink)
+}
+
+func DemoMemoryLimit(w io.Writer) error {
+	runtime.GC()
+	limit := int64(runtimeMemory()) + 32<<20
+	prevLimit := debug.SetMemoryLimit(limit)
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+	fmt.Fprintf(w, "Memory limit set to %d MiB (previous: %d)\n", limit>>20, prevLimit)
+
+	start := gcCycles()
+	churn(8 << 20)
+	fmt.Fprintln(w, "GC cycles while allocating 8 MiB, well under the limit:", gcCycles()-start)
+
+	start = gcCycles()
+	churn(256 << 20)
+	fmt.Fprintln(w, "GC cycles while allocating 256 MiB against the limit:", gcCycles()-start)
+
+	// A negative limit only queries the current setting. Unless GOMEMLIMIT
+	// is set, the previous limit is the default, math.MaxInt64 (no limit).
+	debug.SetMemoryLimit(prevLimit)
+	fmt.Fprintln(w, "Memory limit restored; back to the default of no limit:", debug.SetMemoryLimit(-1) == math.MaxInt64)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 42. encoding/json: Custom Date Format via a Wrapper Type
+//
+// time.Time always marshals as RFC 3339. Wrapping it in a type with its own
+// MarshalJSON and UnmarshalJSON methods changes the wire format, here to a
+// plain "2006-01-02" date, while keeping all of time.Time's methods.
+
+// Date is a time.Time encoded in JSON as a "YYYY-MM-DD" string. The zero Date
+// is encoded as null, and null decodes to the zero Date.
+type Date struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return strconv.AppendQuote(nil, d.Format(time.DateOnly)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("date must be a JSON string: %s", data)
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+func DemoCustomJSONTime(w io.Writer) error {
+	type invoice struct {
+		ID     int  `json:"id"`
+		Issued Date `json:"issued"`
+		Paid   Date `json:"paid"`
+	}
+	in := invoice{ID: 7, Issued: Date{time.Date(2025, time.February, 11, 0, 0, 0, 0, time.UTC)}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("JSON encode: %w", err)
+	}
+	fmt.Fprintln(w, "Invoice JSON:", string(data))
+
+	var out invoice
+	if err := json.Unmarshal(data, &out); err != nil {
+		return fmt.Errorf("JSON decode: %w", err)
+	}
+	if !out.Issued.Equal(in.Issued.Time) || !out.Paid.IsZero() {
+		return fmt.Errorf("round trip: got issued=%v paid=%v, want issued=%v and a zero paid date", out.Issued, out.Paid, in.Issued)
+	}
+	fmt.Fprintf(w, "Round trip: issued=%s, paid is zero\n", out.Issued.Format(time.DateOnly))
+
+	if err := json.Unmarshal([]byte(`{"id": 8, "issued": "2025-02-30"}`), &out); err != nil {
+		fmt.Fprintln(w, "Invalid date rejected:", err)
+	} else {
+		return errors.New("invalid date 2025-02-30 unexpectedly decoded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 43. go/scanner: Tokenizing Go Source
+//
+// go/scanner is the lexer underneath go/parser. With the ScanComments mode it
+// also reports comments, and string literals are returned exactly as written,
+// escapes included.
+func DemoScanner(w io.Writer) error {
+	src := []byte(`x := "tab:\t quote:\"" // greet` + "\n")
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("snippet.go", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	fmt.Fprintln(w, "Tokens:")
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			lit = "(inserted at newline)"
+		}
+		fmt.Fprintf(w, "  %-16s %-8s %s\n", fset.Position(pos), tok, lit)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 44. sync.WaitGroup.Go Across Toolchains
+//
+// Go 1.25 adds WaitGroup.Go, which folds Add, go, and Done into one call.
+// goAll is built from waitgroup_go125.go when the toolchain has it, and from
+// waitgroup_go124.go, using the classic Add/Done pattern, otherwise.
+func DemoWaitGroupGo(w io.Writer) error {
+	const workers = 8
+	var completed atomic.Int64
+	results := make([]int, workers)
+
+	fns := make([]func(), workers)
+	for i := range fns {
+		fns[i] = func() {
+			results[i] = i * i
+			completed.Add(1)
+		}
+	}
+	goAll(fns...)
+
+	if n := completed.Load(); n != workers {
+		return fmt.Errorf("%d of %d workers completed", n, workers)
+	}
+	for i, r := range results {
+		if r != i*i {
+			return fmt.Errorf("worker %d stored %d, want %d", i, r, i*i)
+		}
+	}
+	fmt.Fprintf(w, "Launched %d workers with %s: %d completed, results %v\n",
+		workers, waitGroupStyle, completed.Load(), results)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 45. Hashing a Directory Tree: os.Root.FS and crypto/sha3
+//
+// fs.WalkDir visits entries in lexical order, so walking a sandboxed
+// directory through Root.FS and folding each file's SHA3-256 digest into a
+// root digest gives a stable fingerprint of the whole tree.
+
+// treeHash returns a Merkle-style SHA3-256 digest of the regular files in
+// fsys and the number of files hashed. Each file contributes a leaf hash of
+// its path and content digest; the root hashes the leaves in walk order.
+func treeHash(fsys fs.FS) ([]byte, int, error) {
+	root := sha3.New256()
+	root.Write([]byte{1}) // domain-separate the root from leaves
+	files := 0
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		content := sha3.New256()
+		if _, err := io.Copy(content, f); err != nil {
+			return err
+		}
+
+		leaf := sha3.New256()
+		leaf.Write([]byte{0})
+		leaf.Write([]byte(path))
+		leaf.Write([]byte{0})
+		leaf.Write(content.Sum(nil))
+		root.Write(leaf.Sum(nil))
+		files++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return root.Sum(nil), files, nil
+}
+
+func DemoTreeHash(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-treehash")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+
+	for _, d := range []string{"docs", "empty", "src", "src/util"} {
+		if err := root.Mkdir(d, 0o755); err != nil {
+			return fmt.Errorf("creating directory: %w", err)
+		}
+	}
+	files := map[string]string{
+		"docs/readme.md":   "# Demo\n",
+		"src/main.go":      "package main\n",
+		"src/util/util.go": "package util\n",
+	}
+	for name, content := range files {
+		f, err := root.Create(name)
+		if err != nil {
+			return fmt.Errorf("creating file: %w", err)
+		}
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing file: %w", err)
+		}
+	}
+
+	first, n, err := treeHash(root.FS())
+	if err != nil {
+		return fmt.Errorf("tree hash: %w", err)
+	}
+	second, _, err := treeHash(root.FS())
+	if err != nil {
+		return fmt.Errorf("tree hash: %w", err)
+	}
+	fmt.Fprintf(w, "Tree hash of %d files: %x\n", n, first)
+	fmt.Fprintln(w, "Tree hash is deterministic across walks:", bytes.Equal(first, second))
+
+	empty, err := fs.Sub(root.FS(), "empty")
+	if err != nil {
+		return fmt.Errorf("opening subtree: %w", err)
+	}
+	digest, n, err := treeHash(empty)
+	if err != nil {
+		return fmt.Errorf("tree hash: %w", err)
+	}
+	fmt.Fprintf(w, "Tree hash of empty directory (%d files): %x\n", n, digest)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 46. net/url: JoinPath and ResolveReference
+//
+// url.JoinPath (Go 1.19) appends path segments to a base URL, normalizing
+// duplicate slashes and escaping each segment. ResolveReference resolves a
+// relative reference the way a browser does (RFC 3986).
+func DemoURLBuild(w io.Writer) error {
+	joins := [][]string{
+		{"https://api.example.com/v1/", "/users/", "42"},
+		{"https://api.example.com/v1", "files", "annual report.pdf"},
+		// ".." is cleaned against the joined path, so it can climb out of the
+		// base path (though never above the host root). Validate untrusted
+		// segments before joining them.
+		{"https://api.example.com/v1/users", "../admin"},
+		{"https://api.example.com/", "../../etc/passwd"},
+	}
+	for _, j := range joins {
+		joined, err := url.JoinPath(j[0], j[1:]...)
+		if err != nil {
+			return fmt.Errorf("JoinPath: %w", err)
+		}
+		fmt.Fprintf(w, "JoinPath(%q, %q) = %s\n", j[0], j[1:], joined)
+	}
+
+	base, err := url.Parse("https://example.com/docs/guide/intro.html")
+	if err != nil {
+		return fmt.Errorf("URL parse: %w", err)
+	}
+	for _, ref := range []string{"setup.html", "../api/", "/blog?page=2", "//cdn.example.com/app.js"} {
+		rel, err := url.Parse(ref)
+		if err != nil {
+			return fmt.Errorf("URL parse: %w", err)
+		}
+		fmt.Fprintf(w, "ResolveReference(%q) = %s\n", ref, base.ResolveReference(rel))
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 47. reflect: Value.Clear
+//
+// reflect.Value.Clear (Go 1.21) is the reflective form of the clear builtin:
+// it deletes every map entry, or zeroes every slice element while keeping the
+// length. That is handy for generic reset code that only sees values at run
+// time, such as clearing every collection field of a struct in place.
+
+// clearCollections clears every map and slice field of the struct that ptr
+// points to.
+func clearCollections(ptr any) {
+	v := reflect.ValueOf(ptr).Elem()
+	for i := range v.NumField() {
+		if f := v.Field(i); f.Kind() == reflect.Map || f.Kind() == reflect.Slice {
+			f.Clear()
+		}
+	}
+}
+
+func DemoReflectClear(w io.Writer) error {
+	type session struct {
+		User    string
+		Scores  []int
+		Flags   map[string]bool
+		Pending map[string]int // never initialized
+	}
+	s := session{
+		User:   "gopher",
+		Scores: []int{3, 1, 4},
+		Flags:  map[string]bool{"admin": true, "beta": false},
+	}
+	fmt.Fprintf(w, "Before reflect Clear: %+v\n", s)
+	// Clearing the nil Pending map is a no-op, just like clear(nilMap).
+	clearCollections(&s)
+	if !slices.Equal(s.Scores, []int{0, 0, 0}) || len(s.Flags) != 0 || s.Pending != nil {
+		return fmt.Errorf("after reflect Clear: %+v, want zeroed Scores, empty Flags and a nil Pending", s)
+	}
+	fmt.Fprintf(w, "After reflect Clear:  %+v (Pending still nil)\n", s)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 48. Iterators and Goroutines: A Bounded Worker Pool
+//
+// ProcessPool pulls work items from any iter.Seq and hands them to a fixed
+// number of workers over a channel. Because the sequence is pulled lazily, it
+// may even be infinite: the pool stops pulling as soon as the context is
+// canceled, lets in-flight work finish, and shuts its goroutines down.
+
+// Primes yields the prime numbers in increasing order, without end.
+func Primes() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		var found []int
+		for n := 2; ; n++ {
+			isPrime := true
+			for _, p := range found {
+				if p*p > n {
+					break
+				}
+				if n%p == 0 {
+					isPrime = false
+					break
+				}
+			}
+			if isPrime {
+				found = append(found, n)
+				if !yield(n) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ProcessPool applies work to every item of seq using the given number of
+// worker goroutines and returns the results in input order. If ctx is
+// canceled, it stops pulling from seq and returns the results of the items
+// already started together with ctx.Err().
+func ProcessPool[T, R any](ctx context.Context, seq iter.Seq[T], workers int, work func(T) R) ([]R, error) {
+	type job struct {
+		index int
+		item  T
+	}
+	type result struct {
+		index int
+		value R
+	}
+	jobs := make(chan job)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- result{j.index, work(j.item)}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		i := 0
+		for item := range seq {
+			select {
+			case jobs <- job{i, item}:
+				i++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Every dispatched job completes, so the indexes received are contiguous.
+	var out []R
+	for r := range results {
+		if r.index >= len(out) {
+			out = append(out, make([]R, r.index+1-len(out))...)
+		}
+		out[r.index] = r.value
+	}
+	return out, ctx.Err()
+}
+
+// goroutinesSettled waits briefly for the number of goroutines to fall to n,
+// giving exiting goroutines time to finish, and reports whether it did.
+func goroutinesSettled(n int) bool {
+	for range 100 {
+		if runtime.NumGoroutine() <= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func DemoWorkerPool(w io.Writer) error {
+	// isMersennePrime reports whether 2^p - 1 is prime.
+	isMersennePrime := func(p int) bool {
+		m := new(big.Int).Lsh(big.NewInt(1), uint(p))
+		return m.Sub(m, big.NewInt(1)).ProbablyPrime(20)
+	}
+
+	results, err := ProcessPool(context.Background(), Take(Primes(), 12), 4, isMersennePrime)
+	if err != nil {
+		return fmt.Errorf("worker pool: %w", err)
+	}
+	fmt.Fprintln(w, "Is 2^p-1 prime for the first 12 primes p:", results)
+
+	// With an unbounded input, only cancellation ends the pool.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	before := runtime.NumGoroutine()
+	primes, err := ProcessPool(ctx, Primes(), 4, func(p int) int {
+		time.Sleep(time.Millisecond)
+		return p
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("worker pool over infinite Primes: want a deadline error, got %v", err)
+	}
+	if !goroutinesSettled(before) {
+		return errors.New("worker pool over infinite Primes leaked goroutines")
+	}
+	fmt.Fprintf(w, "Worker pool over infinite Primes stopped (%v) after %d items, no goroutines leaked\n", err, len(primes))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 49. time: Garbage Collection of Unstopped Timers
+//
+// Before Go 1.23, a timer that was never stopped stayed in the runtime's
+// timer heap, and kept its memory, until it fired: dropping 100,000 one-hour
+// timers would pin them all for an hour. The leak was in memory only; pending
+// timers never needed goroutines, so the goroutine count stayed flat then
+// too. Since Go 1.23 (for modules declaring go 1.23 or later), timers that
+// are no longer referenced are garbage collected even if they have not fired
+// or been stopped.
+
+// timerMetrics reads the heap memory occupied by live objects as of the most
+// recent GC and the current number of goroutines. runtime/metrics has no
+// timer count, so pending timers show up through the memory they hold.
+func timerMetrics() (liveHeap, goroutines uint64) {
+	samples := []metrics.Sample{
+		{Name: "/gc/heap/live:bytes"},
+		{Name: "/sched/goroutines:goroutines"},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64(), samples[1].Value.Uint64()
+}
+
+func DemoTimerGC(w io.Writer) error {
+	const n = 100_000
+	runtime.GC()
+	base, baseG := timerMetrics()
+
+	timers := make([]*time.Timer, n)
+	for i := range timers {
+		timers[i] = time.NewTimer(time.Hour)
+	}
+	runtime.GC()
+	held, heldG := timerMetrics()
+	runtime.KeepAlive(timers)
+
+	// Drop every reference without calling Stop.
+	timers = nil
+	runtime.GC()
+	released, releasedG := timerMetrics()
+
+	fmt.Fprintf(w, "Live heap: %d KiB before, %d KiB with %d pending timers, %d KiB after dropping them unstopped\n",
+		base>>10, held>>10, n, released>>10)
+	fmt.Fprintf(w, "Goroutines: %d before, %d with pending timers, %d after\n", baseG, heldG, releasedG)
+	if released >= base+(held-base)/10 {
+		return fmt.Errorf("unstopped timers were not collected: live heap %d KiB, was %d KiB before creating them", released>>10, base>>10)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 50. crypto/hmac: Signed API Tokens
+//
+// A token carries a payload (subject and expiry) and an HMAC-SHA256 tag over
+// it, both base64url-encoded. The MAC key is derived from a master secret with
+// crypto/hkdf, so the same secret can safely key other purposes too, and tags
+// are compared in constant time with hmac.Equal.
+
+var (
+	errTokenMalformed = errors.New("token: malformed")
+	errTokenSignature = errors.New("token: invalid signature")
+	errTokenExpired   = errors.New("token: expired")
+)
+
+// tokenKey derives the token MAC key from a master secret.
+func tokenKey(master []byte) ([]byte, error) {
+	return hkdf.Key(sha256.New, master, nil, "go124 api token v1", sha256.Size)
+}
+
+// SignToken returns a token for subject that expires at expires.
+func SignToken(key []byte, subject string, expires time.Time) string {
+	payload := fmt.Sprintf("%s|%d", subject, expires.Unix())
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(payload)) + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyToken checks the signature and expiry of token at time now and
+// returns its subject.
+func VerifyToken(key []byte, token string, now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	encPayload, encTag, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errTokenMalformed
+	}
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return "", errTokenMalformed
+	}
+	tag, err := enc.DecodeString(encTag)
+	if err != nil {
+		return "", errTokenMalformed
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return "", errTokenSignature
+	}
+
+	// The subject may itself contain "|", so split at the last one.
+	i := bytes.LastIndexByte(payload, '|')
+	if i < 0 {
+		return "", errTokenMalformed
+	}
+	subject := string(payload[:i])
+	unix, err := strconv.ParseInt(string(payload[i+1:]), 10, 64)
+	if err != nil {
+		return "", errTokenMalformed
+	}
+	if !now.Before(time.Unix(unix, 0)) {
+		return "", errTokenExpired
+	}
+	return subject, nil
+}
+
+func DemoAPIToken(w io.Writer) error {
+	key, err := NewTokenKey(nil)
+	if err != nil {
+		return fmt.Errorf("token key: %w", err)
+	}
+
+	now := time.Now()
+	token := SignToken(key, "user-42", now.Add(time.Hour))
+	fmt.Fprintln(w, "API token:", token)
+	subject, err := VerifyToken(key, token, now)
+	if err != nil || subject != "user-42" {
+		return fmt.Errorf("verifying token: got %q, %v; want \"user-42\"", subject, err)
+	}
+	fmt.Fprintf(w, "Verified token: subject=%q\n", subject)
+
+	// Swap in a different subject but keep the original signature.
+	_, tag, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("admin|%d", now.Add(time.Hour).Unix()))) + "." + tag
+	if _, err := VerifyToken(key, forged, now); err != nil {
+		fmt.Fprintln(w, "Tampered token rejected:", err)
+	} else {
+		return errors.New("tampered token unexpectedly verified")
+	}
+
+	expired := SignToken(key, "user-42", now.Add(-time.Minute))
+	if _, err := VerifyToken(key, expired, now); err != nil {
+		fmt.Fprintln(w, "Expired token rejected:", err)
+	} else {
+		return errors.New("expired token unexpectedly verified")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 51. encoding/json: MarshalIndent and Map Key Order
+//
+// encoding/json documents that map keys are sorted, so marshaling a map is
+// deterministic even though map iteration is not. Keys are sorted by their
+// string form, which means integer keys sort lexically: "10" before "2".
+func DemoJSONIndent(w io.Writer) error {
+	config := map[string]any{
+		"service": "billing",
+		"replicas": map[string]int{
+			"us-east":  3,
+			"eu-west":  2,
+			"ap-south": 1,
+		},
+		"limits": map[string]any{"memory": "512Mi", "cpu": 0.5},
+		"ports":  []int{8080, 9090},
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON encode: %w", err)
+	}
+	fmt.Fprintln(w, "MarshalIndent with sorted keys:")
+	fmt.Fprintln(w, string(data))
+
+	byShard := map[int]string{2: "two", 10: "ten", 1: "one"}
+	data, err = json.Marshal(byShard)
+	if err != nil {
+		return fmt.Errorf("JSON encode: %w", err)
+	}
+	fmt.Fprintln(w, "Integer keys sort as strings:", string(data))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 52. net/netip: Migrating from net.ParseCIDR to netip.ParsePrefix
+//
+// net.ParseCIDR returns a net.IP and a *net.IPNet, both backed by slices, so
+// they allocate and cannot be compared with ==. netip.ParsePrefix returns a
+// small comparable value and does not allocate.
+func DemoCIDRMigration(w io.Writer) error {
+	const cidr = "10.1.2.3/16"
+
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("ParseCIDR: %w", err)
+	}
+	fmt.Fprintf(w, "net.ParseCIDR(%q): ip=%v net=%v\n", cidr, ip, ipNet)
+
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("ParsePrefix: %w", err)
+	}
+	// Unlike ParseCIDR, ParsePrefix keeps the host bits; Masked drops them.
+	fmt.Fprintf(w, "netip.ParsePrefix(%q): prefix=%v masked=%v\n", cidr, prefix, prefix.Masked())
+	if prefix.Masked() != netip.MustParsePrefix("10.1.0.0/16") {
+		return fmt.Errorf("%v masked to %v, want 10.1.0.0/16", prefix, prefix.Masked())
+	}
+	// Being a plain value, a Prefix compares with == and parses without
+	// allocating; BenchmarkParseCIDR measures the difference.
+	fmt.Fprintln(w, "Prefixes compare with ==:", prefix.Masked(), "== 10.1.0.0/16")
+
+	a, b, c := netip.MustParsePrefix("10.1.0.0/16"), netip.MustParsePrefix("10.1.200.0/24"), netip.MustParsePrefix("10.2.0.0/16")
+	if !a.Overlaps(b) || a.Overlaps(c) {
+		return fmt.Errorf("overlaps: %v and %v = %t, %v and %v = %t; want true, false", a, b, a.Overlaps(b), a, c, a.Overlaps(c))
+	}
+	fmt.Fprintf(w, "%v overlaps %v; %v does not overlap %v\n", a, b, a, c)
+
+	if _, err := netip.ParsePrefix("10.1.0.0/33"); err != nil {
+		fmt.Fprintln(w, "Invalid prefix rejected:", err)
+	} else {
+		return errors.New("prefix 10.1.0.0/33 unexpectedly parsed")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 53. Generics and sync.Map: Memoization
+//
+// Memoize packages the lazyLoad pattern from the lazy initialization demo as
+// a reusable wrapper: any func(K) V becomes a concurrency-safe cached version
+// that computes each key exactly once.
+
+// Memoize returns a function that calls f at most once per key and returns
+// the cached result afterwards. It is safe for concurrent use; concurrent
+// calls with a new key wait for a single call of f.
+func Memoize[K comparable, V any](f func(K) V) func(K) V {
+	var cache sync.Map
+	return func(key K) V {
+		return lazyLoad(&cache, key, f)
+	}
+}
+
+func DemoMemoize(w io.Writer) error {
+	var calls atomic.Int64
+	var fib func(int) *big.Int
+	fib = Memoize(func(n int) *big.Int {
+		calls.Add(1)
+		if n < 2 {
+			return big.NewInt(int64(n))
+		}
+		return new(big.Int).Add(fib(n-1), fib(n-2))
+	})
+
+	const n = 90
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fib(n)
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != n+1 {
+		return fmt.Errorf("memoized fib made %d underlying calls, want %d", got, n+1)
+	}
+	fmt.Fprintf(w, "Memoized fib(%d) = %v\n", n, fib(n))
+	fmt.Fprintf(w, "Underlying calls from 16 concurrent callers: %d (one per key 0..%d)\n", calls.Load(), n)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 54. log/slog: Runtime Level Changes with LevelVar
+//
+// A handler given a *slog.LevelVar as its Level consults it on every record,
+// so verbosity can be raised or lowered while the program runs, e.g. from an
+// admin endpoint, without rebuilding the logger.
+func DemoSlogLevelVar(w io.Writer) error {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar) // defaults to Info
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{} // drop the time for stable output
+			}
+			return a
+		},
+	}))
+
+	logger.Debug("cache miss", "key", "user:1")
+	logger.Info("request served", "status", 200)
+	level.Set(slog.LevelDebug)
+	logger.Debug("cache miss", "key", "user:2")
+	level.Set(slog.LevelWarn)
+	logger.Info("request served", "status", 200)
+	logger.Warn("slow request", "ms", 950)
+
+	out := buf.String()
+	fmt.Fprint(w, "slog records that passed the LevelVar:\n", out)
+	if strings.Contains(out, "user:1") || !strings.Contains(out, "user:2") {
+		return errors.New("records at Debug did not pass only while the level was Debug")
+	}
+	fmt.Fprintln(w, "Debug passed only while level was Debug")
+	if n := strings.Count(out, "request served"); n != 1 {
+		return fmt.Errorf("%d Info records passed, want 1 before the level was raised to Warn", n)
+	}
+	fmt.Fprintln(w, "Info filtered after raising level to Warn")
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 55. bytes.Reader: Random Access over Appended Content
+//
+// Content built with the AppendText methods shown earlier is a plain []byte,
+// and bytes.Reader turns it into an io.ReadSeeker and io.ReaderAt for random
+// access without copying.
+func DemoBytesReader(w io.Writer) error {
+	var buf []byte
+	var err error
+	if buf, err = (demoStruct{Value: 7}).AppendText(buf); err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	buf = append(buf, ' ')
+	if buf, err = netip.MustParseAddr("2001:db8::1").AppendText(buf); err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	buf = append(buf, ' ')
+	if buf, err = time.Date(2025, time.February, 11, 0, 0, 0, 0, time.UTC).AppendText(buf); err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	fmt.Fprintf(w, "Appended content (%d bytes): %s\n", len(buf), buf)
+
+	r := bytes.NewReader(buf)
+	// Seek past "demoStruct(7) " to the address.
+	if _, err := r.Seek(14, io.SeekStart); err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	addr := make([]byte, 11)
+	if _, err := io.ReadFull(r, addr); err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	fmt.Fprintf(w, "Read after Seek(14): %q\n", addr)
+
+	year := make([]byte, 4)
+	if _, err := r.ReadAt(year, int64(len(buf)-20)); err != nil {
+		return fmt.Errorf("ReadAt: %w", err)
+	}
+	fmt.Fprintf(w, "ReadAt(len-20): %q (Reader offset unchanged, %d bytes unread)\n", year, r.Len())
+
+	// Seeking past the end is allowed; reading there reports io.EOF.
+	pos, err := r.Seek(100, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	n, err := r.Read(make([]byte, 1))
+	if n != 0 || err != io.EOF {
+		return fmt.Errorf("read past the end: got n=%d err=%v, want 0 and io.EOF", n, err)
+	}
+	fmt.Fprintf(w, "Read at offset %d past the end: n=%d err=%v\n", pos, n, err)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 56. go/importer: Introspecting a Standard Library Package
+//
+// An importer loads a package's exported API from compiler export data
+// without parsing its source. Walking the package scope lists every exported
+// object, which can be filtered by kind.
+func DemoImporter(w io.Writer) error {
+	imp := importer.ForCompiler(token.NewFileSet(), "gc", nil)
+	pkg, err := imp.Import("strings")
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	var funcs []string
+	scope := pkg.Scope()
+	for _, name := range scope.Names() { // Names is sorted
+		if fn, ok := scope.Lookup(name).(*types.Func); ok && fn.Exported() {
+			funcs = append(funcs, name)
+		}
+	}
+	fmt.Fprintf(w, "Package strings exports %d functions; the first few: %v\n", len(funcs), funcs[:min(8, len(funcs))])
+
+	if _, err := imp.Import("example.com/no/such/package"); err != nil {
+		fmt.Fprintln(w, "Importing a nonexistent package fails:", err)
+	} else {
+		return errors.New("importing a nonexistent package unexpectedly succeeded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 57. Generics and Iterators: A Set Type
+//
+// Set[T] is a map-backed set whose All method returns an iter.Seq, so its
+// elements can be ranged over directly or passed to helpers such as
+// slices.Sorted and slices.Collect.
+
+// Set is a set of comparable values. The zero value is an empty set ready
+// to use.
+type Set[T comparable] struct {
+	m map[T]struct{}
+}
+
+// NewSet returns a set containing items.
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{}
+	for _, v := range items {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add adds v to the set.
+func (s *Set[T]) Add(v T) {
+	if s.m == nil {
+		s.m = make(map[T]struct{})
+	}
+	s.m[v] = struct{}{}
+}
+
+// Remove removes v from the set, if present.
+func (s *Set[T]) Remove(v T) {
+	delete(s.m, v)
+}
+
+// Contains reports whether v is in the set.
+func (s *Set[T]) Contains(v T) bool {
+	_, ok := s.m[v]
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (s *Set[T]) Len() int {
+	return len(s.m)
+}
+
+// All returns an iterator over the elements of the set, in no particular
+// order.
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s.m {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Union returns a new set with the elements that are in a or b.
+func Union[T comparable](a, b *Set[T]) *Set[T] {
+	out := NewSet[T]()
+	for v := range a.All() {
+		out.Add(v)
+	}
+	for v := range b.All() {
+		out.Add(v)
+	}
+	return out
+}
+
+// Intersection returns a new set with the elements that are in both a and b.
+func Intersection[T comparable](a, b *Set[T]) *Set[T] {
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	out := NewSet[T]()
+	for v := range a.All() {
+		if b.Contains(v) {
+			out.Add(v)
+		}
+	}
+	return out
+}
+
+func DemoSet(w io.Writer) error {
+	backend := NewSet("go", "rust", "java", "python")
+	data := NewSet("python", "r", "sql", "go")
+	backend.Remove("java")
+
+	fmt.Fprintln(w, "Set contains go:", backend.Contains("go"), "java:", backend.Contains("java"))
+	fmt.Fprintln(w, "Union:", slices.Sorted(Union(backend, data).All()))
+	fmt.Fprintln(w, "Intersection:", slices.Sorted(Intersection(backend, data).All()))
+
+	var empty Set[string]
+	fmt.Fprintf(w, "With an empty set: union=%v intersection=%v len=%d\n",
+		slices.Sorted(Union(&empty, data).All()), slices.Sorted(Intersection(&empty, data).All()), empty.Len())
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 58. crypto/x509: Verifying a Chain through an Intermediate
+//
+// Servers usually present their leaf certificate plus any intermediates, and
+// clients trust only the root. Certificate.Verify builds the chain from the
+// leaf through VerifyOptions.Intermediates up to VerifyOptions.Roots.
+
+// issueCert creates a certificate from tmpl with a fresh P-256 key, signed by
+// parent and parentKey, or self-signed if parent is nil.
+func issueCert(tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(crand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func DemoCertChain(w io.Writer) error {
+	now := time.Now()
+	caTemplate := func(serial int64, name string) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(24 * time.Hour),
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+	}
+
+	root, rootKey, err := issueCert(caTemplate(1, "Demo Root CA"), nil, nil)
+	if err != nil {
+		return fmt.Errorf("creating root: %w", err)
+	}
+	intermediate, intermediateKey, err := issueCert(caTemplate(2, "Demo Intermediate CA"), root, rootKey)
+	if err != nil {
+		return fmt.Errorf("creating intermediate: %w", err)
+	}
+	leaf, _, err := issueCert(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "api.demo.test"},
+		DNSNames:     []string{"api.demo.test"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, intermediate, intermediateKey)
+	if err != nil {
+		return fmt.Errorf("creating leaf: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate)
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       "api.demo.test",
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return fmt.Errorf("chain verification: %w", err)
+	}
+	for _, chain := range chains {
+		names := make([]string, len(chain))
+		for i, cert := range chain {
+			names[i] = cert.Subject.CommonName
+		}
+		fmt.Fprintln(w, "Verified chain:", strings.Join(names, " -> "))
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "api.demo.test", Roots: roots}); err != nil {
+		fmt.Fprintln(w, "Verification without the intermediate fails:", err)
+	} else {
+		return errors.New("verification without the intermediate unexpectedly succeeded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 59. Text Template: break and continue in range
+//
+// {{break}} ends a {{range}} loop early and {{continue}} skips to the next
+// iteration (both since Go 1.18). They combine with range over an integer,
+// which templates support since Go 1.22.
+func DemoTemplateBreakContinue(w io.Writer) error {
+	const tmplText = `Tasks: {{range .}}{{if .Done}}{{continue}}{{end}}{{if .Blocked}}{{break}}{{end}}{{.Name}} {{end}}
+Odd numbers below 10, stopping at 7: {{range $i := 10}}{{if eq (mod $i 2) 0}}{{continue}}{{end}}{{if gt $i 7}}{{break}}{{end}}{{$i}} {{end}}`
+
+	type task struct {
+		Name          string
+		Done, Blocked bool
+	}
+	tasks := []task{
+		{Name: "design"},
+		{Name: "review", Done: true},
+		{Name: "build"},
+		{Name: "deploy", Blocked: true},
+		{Name: "announce"},
+	}
+
+	tmpl, err := template.New("control").Funcs(template.FuncMap{
+		"mod": func(a, b int) int { return a % b },
+	}).Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, tasks); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	fmt.Fprintln(w, out.String())
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 60. io.MultiWriter: Tee-ing Output to Stdout and a Buffer
+//
+// io.MultiWriter duplicates each write to several writers, which makes it
+// easy to show output and record it at the same time. Writes go to the
+// writers in order and stop at the first error, so a failing writer keeps
+// the later ones from seeing the data. main tees the whole RunAll output this
+// way; the demo tees a single demo.
+
+// errWriter is an io.Writer that always fails with err.
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func DemoMultiWriter(w io.Writer) error {
+	// Any demo can be teed, since each one writes only to its writer.
+	var capture bytes.Buffer
+	if err := DemoSlog(io.MultiWriter(w, &capture)); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "MultiWriter captured %d bytes of DemoSlog output: %q\n", capture.Len(), capture.String())
+
+	var after bytes.Buffer
+	failing := io.MultiWriter(errWriter{errors.New("disk full")}, &after)
+	err := notImplemented("Teed demo")(failing)
+	if err == nil || after.Len() != 0 {
+		return fmt.Errorf("MultiWriter with a failing first writer: err=%v, later writer got %d bytes; want an error and 0 bytes", err, after.Len())
+	}
+	fmt.Fprintf(w, "MultiWriter with a failing first writer: err=%v, later writer got %d bytes\n", err, after.Len())
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 61. maphash: Seeds and Hash Stability
+//
+// Every maphash.Seed is random, and a zero maphash.Hash picks a random seed
+// on first use. That is deliberate: if attackers could predict hash values,
+// they could craft keys that all collide and degrade a hash table to linear
+// time (hash flooding). The flip side is that hashes are only reproducible
+// while the same Seed value is reused. Seeds cannot be serialized, so maphash
+// output must never be persisted or compared across processes; use a
+// cryptographic or fixed-key hash for that.
+func DemoHashSeedStability(w io.Writer) error {
+	const key = "order-1234"
+
+	var first, second maphash.Hash // each gets its own random seed
+	first.WriteString(key)
+	second.WriteString(key)
+	fmt.Fprintln(w, "Two zero-value Hashes agree:", first.Sum64() == second.Sum64())
+
+	fmt.Fprintln(w, "Fresh seeds agree:", maphash.String(maphash.MakeSeed(), key) == maphash.String(maphash.MakeSeed(), key))
+
+	// Make the seed once, store it, and reuse it for reproducible hashes.
+	seed := maphash.MakeSeed()
+	var h maphash.Hash
+	h.SetSeed(seed)
+	h.WriteString(key)
+	fmt.Fprintln(w, "Stored seed agrees across calls:",
+		maphash.String(seed, key) == maphash.String(seed, key) && h.Sum64() == maphash.String(seed, key))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 62. Generics and Iterators: A Ring Buffer
+//
+// RingBuffer[T] keeps the most recent values up to a fixed capacity,
+// overwriting the oldest when full. Drain returns an iter.Seq that removes
+// values as it yields them, so a consumer can stop early and leave the rest
+// buffered.
+
+// RingBuffer is a fixed-capacity FIFO buffer that overwrites its oldest
+// value when full. Create ring buffers with NewRingBuffer.
+type RingBuffer[T any] struct {
+	buf   []T
+	start int // index of the oldest value
+	size  int
+}
+
+// NewRingBuffer returns an empty ring buffer holding up to capacity values.
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	return &RingBuffer[T]{buf: make([]T, max(capacity, 1))}
+}
+
+// Push appends v, overwriting the oldest value if the buffer is full.
+func (r *RingBuffer[T]) Push(v T) {
+	end := (r.start + r.size) % len(r.buf)
+	r.buf[end] = v
+	if r.size < len(r.buf) {
+		r.size++
+	} else {
+		r.start = (r.start + 1) % len(r.buf)
+	}
+}
+
+// Len returns the number of buffered values.
+func (r *RingBuffer[T]) Len() int {
+	return r.size
+}
+
+// Drain returns an iterator that removes and yields the buffered values from
+// oldest to newest. Values not yet yielded when the loop stops stay buffered.
+func (r *RingBuffer[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for r.size > 0 {
+			v := r.buf[r.start]
+			var zero T
+			r.buf[r.start] = zero // release references held by the slot
+			r.start = (r.start + 1) % len(r.buf)
+			r.size--
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func DemoRingBuffer(w io.Writer) error {
+	rb := NewRingBuffer[int](4)
+	for i := 1; i <= 6; i++ {
+		rb.Push(i) // 1 and 2 are overwritten by 5 and 6
+	}
+	var firstTwo []int
+	for v := range rb.Drain() {
+		firstTwo = append(firstTwo, v)
+		if len(firstTwo) == 2 {
+			break
+		}
+	}
+	fmt.Fprintln(w, "Ring buffer drained first two:", firstTwo, "remaining:", rb.Len())
+
+	rb.Push(7)
+	rb.Push(8)
+	rb.Push(9) // full again: overwrites 5, wrapping around the backing array
+	fmt.Fprintln(w, "Ring buffer drained after wraparound:", slices.Collect(rb.Drain()))
+	fmt.Fprintln(w, "Draining an empty ring buffer:", slices.Collect(rb.Drain()))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 63. encoding: BinaryAppender over JSON via base64
+//
+// A type with a compact binary form can implement encoding.BinaryAppender
+// (new in Go 1.24) and BinaryMarshaler, and reuse that form in JSON: a []byte
+// marshals as a base64 string, so MarshalJSON only has to encode its binary
+// bytes, and UnmarshalJSON gets base64 validation for free.
+
+// Version is a semantic version with a 6-byte big-endian binary encoding.
+type Version struct {
+	Major, Minor, Patch uint16
+}
+
+var (
+	_ encoding.BinaryAppender    = Version{}
+	_ encoding.BinaryMarshaler   = Version{}
+	_ encoding.BinaryUnmarshaler = (*Version)(nil)
+)
+
+// AppendBinary implements encoding.BinaryAppender.
+func (v Version) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.BigEndian.AppendUint16(b, v.Major)
+	b = binary.BigEndian.AppendUint16(b, v.Minor)
+	return binary.BigEndian.AppendUint16(b, v.Patch), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (v Version) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 6))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (v *Version) UnmarshalBinary(data []byte) error {
+	if len(data) != 6 {
+		return fmt.Errorf("version: invalid binary length %d", len(data))
+	}
+	v.Major = binary.BigEndian.Uint16(data[0:])
+	v.Minor = binary.BigEndian.Uint16(data[2:])
+	v.Patch = binary.BigEndian.Uint16(data[4:])
+	return nil
+}
+
+// MarshalJSON encodes the binary form as a base64 JSON string.
+func (v Version) MarshalJSON() ([]byte, error) {
+	b, err := v.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(b)
+}
+
+// UnmarshalJSON decodes a base64 JSON string holding the binary form.
+func (v *Version) UnmarshalJSON(data []byte) error {
+	var b []byte
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	return v.UnmarshalBinary(b)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func DemoBinaryJSON(w io.Writer) error {
+	type release struct {
+		Name    string  `json:"name"`
+		Version Version `json:"version"`
+	}
+	data, err := json.Marshal(release{Name: "go124", Version: Version{1, 24, 3}})
+	if err != nil {
+		return fmt.Errorf("JSON encode: %w", err)
+	}
+	fmt.Fprintln(w, "Release JSON with a base64 binary version:", string(data))
+
+	var r release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("JSON decode: %w", err)
+	}
+	fmt.Fprintln(w, "Decoded version:", r.Version)
+
+	if err := json.Unmarshal([]byte(`{"name":"bad","version":"!!not base64!!"}`), &r); err != nil {
+		fmt.Fprintln(w, "Invalid base64 rejected:", err)
+	} else {
+		return errors.New("invalid base64 version unexpectedly decoded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 64. slices: Equal and EqualFunc
+//
+// slices.Equal compares two slices element by element with ==, and
+// slices.EqualFunc takes a comparison function, which also allows the two
+// slices to have different element types. Slices of different lengths are
+// never equal. Because NaN != NaN, float slices containing NaN are unequal
+// even to themselves.
+func DemoSlicesEqual(w io.Writer) error {
+	a, b := []int{1, 2, 3}, []int{1, 2, 3}
+	fmt.Fprintln(w, "slices.Equal([1 2 3], [1 2 3]):", slices.Equal(a, b))
+	fmt.Fprintln(w, "slices.Equal([1 2 3], [1 2]):", slices.Equal(a, b[:2]))
+
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := []user{{1, "alice"}, {2, "bob"}}
+	ids := []int{1, 2}
+	sameID := func(u user, id int) bool { return u.ID == id }
+	fmt.Fprintln(w, "slices.EqualFunc(users, [1 2]) by ID:", slices.EqualFunc(users, ids, sameID))
+	fmt.Fprintln(w, "slices.EqualFunc(users, [2 1]) by ID:", slices.EqualFunc(users, []int{2, 1}, sameID))
+
+	withNaN := []float64{1, math.NaN()}
+	fmt.Fprintln(w, "slices.Equal of a NaN slice with itself:", slices.Equal(withNaN, withNaN))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 65. net/http: Streaming Request Bodies
+//
+// A request body can be any io.Reader. Feeding it from an io.Pipe streams
+// data as it is produced, and since the length is unknown the client sends
+// it with chunked transfer encoding. Canceling the request context aborts
+// the upload midway.
+func DemoStreamUpload(w io.Writer) error {
+	type upload struct {
+		n        int64
+		encoding []string
+		err      error
+	}
+	// The handler signals started once it has read the first byte of a body,
+	// and sends what it read to received when the body ends.
+	started := make(chan struct{}, 1)
+	received := make(chan upload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := io.CopyN(io.Discard, r.Body, 1)
+		started <- struct{}{}
+		if err == nil {
+			var rest int64
+			rest, err = io.Copy(io.Discard, r.Body)
+			n += rest
+		}
+		received <- upload{n, r.TransferEncoding, err}
+		fmt.Fprint(w, n)
+	}))
+	defer srv.Close()
+
+	const chunks = 8
+	chunk := bytes.Repeat([]byte("x"), 16<<10)
+
+	pr, pw := io.Pipe()
+	go func() {
+		for range chunks {
+			if _, err := pw.Write(chunk); err != nil {
+				return
+			}
+		}
+		pw.Close()
+	}()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, pr)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("upload: %w", err)
+	}
+	resp.Body.Close()
+	<-started
+	up := <-received
+	fmt.Fprintf(w, "Streamed upload: server received %d bytes with transfer encoding %v\n", up.n, up.encoding)
+
+	// The producer cancels the upload after its first chunk, once the server
+	// has started reading, so the body is cut off partway.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pr, pw = io.Pipe()
+	go func() {
+		if _, err := pw.Write(chunk); err != nil {
+			return
+		}
+		<-started
+		cancel()
+		pw.CloseWithError(ctx.Err())
+	}()
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, pr)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if _, err := srv.Client().Do(req); err != nil {
+		fmt.Fprintln(w, "Canceled upload error:", err)
+	} else {
+		return errors.New("canceled upload unexpectedly succeeded")
+	}
+	select {
+	case up := <-received:
+		if up.err == nil {
+			return fmt.Errorf("server read the canceled upload without an error (%d of %d bytes)", up.n, chunks*len(chunk))
+		}
+		fmt.Fprintf(w, "Server saw a truncated body: %d of %d bytes, err=%v\n", up.n, chunks*len(chunk), up.err)
+	case <-time.After(time.Second):
+		return errors.New("server never saw the canceled upload")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 66. go/types: Struct Layout with SizesFor
+//
+// types.SizesFor reports the sizes and alignments the gc compiler uses on a
+// given architecture, so a tool can compute struct layouts, padding included,
+// without compiling or running code for that target.
+func DemoStructLayout(w io.Writer) error {
+	const src = `package layout
+
+type Padded struct {
+	Active bool
+	Count  int64
+	Flag   bool
+	Total  int64
+	Small  int16
+}
+
+type Packed struct {
+	Count  int64
+	Total  int64
+	Small  int16
+	Active bool
+	Flag   bool
+}
+`
+	pkg, _, err := typeCheck("layout.go", src)
+	if err != nil {
+		return fmt.Errorf("type check: %w", err)
+	}
+	sizes := types.SizesFor("gc", "amd64")
+	for _, name := range []string{"Padded", "Packed"} {
+		st := pkg.Scope().Lookup(name).Type().Underlying().(*types.Struct)
+		fields := make([]*types.Var, st.NumFields())
+		for i := range fields {
+			fields[i] = st.Field(i)
+		}
+		offsets := sizes.Offsetsof(fields)
+		size := sizes.Sizeof(st)
+		fmt.Fprintf(w, "%s: size=%d align=%d (gc/amd64)\n", name, size, sizes.Alignof(st))
+
+		for i, f := range fields {
+			end := size
+			if i+1 < len(fields) {
+				end = offsets[i+1]
+			}
+			fieldSize := sizes.Sizeof(f.Type())
+			padding := ""
+			if gap := end - offsets[i] - fieldSize; gap > 0 {
+				padding = fmt.Sprintf("  + %d bytes padding", gap)
+			}
+			fmt.Fprintf(w, "  %-6s %-5s offset=%2d size=%d%s\n", f.Name(), f.Type(), offsets[i], fieldSize, padding)
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 67. crypto/rand: Injecting the Randomness Source
+//
+// Functions that take their randomness as an io.Reader, defaulting to
+// crypto/rand.Reader, stay secure in production but can be driven by a fixed
+// reader to get exact, repeatable output. A reader that runs short must be
+// reported as an error, never silently produce weak values.
+
+// randomBytes reads n bytes from r, or from crypto/rand.Reader if r is nil.
+func randomBytes(r io.Reader, n int) ([]byte, error) {
+	if r == nil {
+		r = crand.Reader
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("reading %d random bytes: %w", n, err)
+	}
+	return b, nil
+}
+
+// NewUUID returns a random (version 4) UUID using randomness from r, or from
+// crypto/rand.Reader if r is nil.
+func NewUUID(r io.Reader) (string, error) {
+	b, err := randomBytes(r, 16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// NewTokenKey derives a fresh API token key from a random master secret read
+// from r, or from crypto/rand.Reader if r is nil.
+func NewTokenKey(r io.Reader) ([]byte, error) {
+	master, err := randomBytes(r, 32)
+	if err != nil {
+		return nil, err
+	}
+	return tokenKey(master)
+}
+
+func DemoRandReader(w io.Writer) error {
+	id, err := NewUUID(nil)
+	if err != nil {
+		return fmt.Errorf("UUID: %w", err)
+	}
+	fmt.Fprintln(w, "UUID from crypto/rand:", id)
+
+	// A fixed reader yields the bytes 0x00, 0x01, ... so the output is exact.
+	counting := func(n int) io.Reader {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte(i)
+		}
+		return bytes.NewReader(b)
+	}
+	id, err = NewUUID(counting(16))
+	if err != nil {
+		return fmt.Errorf("UUID: %w", err)
+	}
+	const wantUUID = "00010203-0405-4607-8809-0a0b0c0d0e0f"
+	if id != wantUUID {
+		return fmt.Errorf("UUID from a fixed reader is %s, want %s", id, wantUUID)
+	}
+	fmt.Fprintln(w, "UUID from a fixed reader:", id)
+
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	key1, err1 := NewTokenKey(counting(32))
+	key2, err2 := NewTokenKey(counting(32))
+	if err := errors.Join(err1, err2); err != nil {
+		return fmt.Errorf("token key: %w", err)
+	}
+	token1, token2 := SignToken(key1, "user-42", expires), SignToken(key2, "user-42", expires)
+	if token1 != token2 {
+		return fmt.Errorf("tokens from identical fixed readers differ: %s and %s", token1, token2)
+	}
+	fmt.Fprintln(w, "Tokens from identical fixed readers are identical:", token1)
+
+	if _, err := NewUUID(counting(8)); err != nil {
+		fmt.Fprintln(w, "Short reader rejected:", err)
+	} else {
+		return errors.New("UUID from a short reader unexpectedly succeeded")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 68. time and encoding/json: Zero Times and omitzero
+//
+// The zero time.Time is January 1, year 1, not the Unix epoch, and IsZero
+// only reports true for the former. omitempty never omitted struct values
+// such as time.Time, so unset timestamps used to marshal as
+// "0001-01-01T00:00:00Z". Go 1.24's omitzero tag option omits a field whose
+// value is zero, calling its IsZero method when it has one.
+func DemoZeroTime(w io.Writer) error {
+	var zero time.Time
+	epoch := time.Unix(0, 0).UTC()
+	fmt.Fprintf(w, "Zero time: %v (IsZero=%t)\n", zero, zero.IsZero())
+	fmt.Fprintf(w, "Unix epoch: %v (IsZero=%t)\n", epoch, epoch.IsZero())
+
+	type job struct {
+		Name      string    `json:"name"`
+		Created   time.Time `json:"created"`
+		Started   time.Time `json:"started,omitempty"`
+		Deadline  time.Time `json:"deadline,omitzero"`
+		Completed time.Time `json:"completed,omitzero"`
+	}
+	j := job{
+		Name:      "nightly-backup",
+		Created:   time.Date(2025, time.February, 11, 2, 0, 0, 0, time.UTC),
+		Completed: epoch, // set, even though it is the epoch
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("JSON encode: %w", err)
+	}
+	fmt.Fprintln(w, "Job with unset Started (omitempty) and Deadline (omitzero):", string(data))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 69. Generics and Channels: Pipeline Stages
+//
+// Each generic stage runs in its own goroutine, reads from the previous
+// stage's channel, and closes its output when its input is exhausted or the
+// context is canceled. Every send also watches ctx.Done, so cancellation
+// unwinds the whole pipeline without leaking goroutines.
+
+// Generate sends the values of seq on the returned channel until seq ends or
+// ctx is canceled.
+func Generate[T any](ctx context.Context, seq iter.Seq[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range seq {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Transform sends f(v) for every v received from in.
+func Transform[T, U any](ctx context.Context, in <-chan T, f func(T) U) <-chan U {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for v := range in {
+			select {
+			case out <- f(v):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Filter passes on the values received from in for which keep returns true.
+func Filter[T any](ctx context.Context, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range in {
+			if !keep(v) {
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Collect gathers the values received from in until it is closed. If ctx is
+// canceled first, Collect returns the values gathered so far and ctx.Err().
+func Collect[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	var out []T
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				// Upstream stages close their outputs when ctx is canceled,
+				// so a closed channel can mean cancellation too.
+				return out, ctx.Err()
+			}
+			out = append(out, v)
+		case <-ctx.Done():
+			return out, ctx.Err()
+		}
+	}
+}
+
+func DemoPipeline(w io.Writer) error {
+	before := runtime.NumGoroutine()
+	square := func(n int) int { return n * n }
+	even := func(n int) bool { return n%2 == 0 }
+
+	ctx := context.Background()
+	numbers := Generate(ctx, slices.Values([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}))
+	evenSquares, err := Collect(ctx, Filter(ctx, Transform(ctx, numbers, square), even))
+	if err != nil {
+		return fmt.Errorf("pipeline: %w", err)
+	}
+	fmt.Fprintln(w, "Pipeline even squares of 1..10:", evenSquares)
+
+	// An infinite source runs until the context is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	twinCandidates := Transform(ctx, Generate(ctx, Primes()), func(p int) int { return p + 2 })
+	partial, err := Collect(ctx, twinCandidates)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("pipeline over infinite Primes: got error %v, want a deadline error", err)
+	}
+	fmt.Fprintf(w, "Pipeline over infinite Primes canceled (%v) after %d values\n", err, len(partial))
+	if !goroutinesSettled(before) {
+		return errors.New("pipeline goroutines did not exit after cancellation")
+	}
+	fmt.Fprintln(w, "Pipeline goroutines all exited")
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 70. log/slog: TextHandler vs JSONHandler and ReplaceAttr
+//
+// The same record renders as key=value text or as JSON depending on the
+// handler. HandlerOptions.ReplaceAttr sees every attribute, including those
+// nested in groups, so one function can redact secrets in both formats.
+func DemoSlogHandlers(w io.Writer) error {
+	redact := func(groups []string, a slog.Attr) slog.Attr {
+		switch {
+		case a.Key == slog.TimeKey && len(groups) == 0:
+			return slog.Attr{} // drop the time for stable output
+		case a.Key == "password":
+			return slog.String("password", "REDACTED")
+		}
+		return a
+	}
+	opts := &slog.HandlerOptions{ReplaceAttr: redact}
+
+	var textBuf, jsonBuf bytes.Buffer
+	for _, logger := range []*slog.Logger{
+		slog.New(slog.NewTextHandler(&textBuf, opts)),
+		slog.New(slog.NewJSONHandler(&jsonBuf, opts)),
+	} {
+		logger.Info("login",
+			"user", "gopher",
+			"password", "hunter2",
+			slog.Group("request", "ip", "192.0.2.1", slog.Group("auth", "method", "basic", "password", "hunter2")),
+		)
+	}
+
+	fmt.Fprint(w, "TextHandler: ", textBuf.String())
+	fmt.Fprint(w, "JSONHandler: ", jsonBuf.String())
+	if strings.Contains(textBuf.String()+jsonBuf.String(), "hunter2") {
+		return errors.New("password leaked into the log output")
+	}
+	fmt.Fprintln(w, "Password redacted in both, including the nested group")
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 71. runtime: AddCleanup Alongside SetFinalizer
+//
+// An object can have at most one finalizer (setting a second one is a fatal
+// error) but any number of cleanups. When it has both, the finalizer runs
+// first; because a finalizer can resurrect its object, the cleanups only run
+// once the object is unreachable again, after a later GC cycle. This is worth
+// knowing when migrating code off finalizers piecemeal.
+
+// finalizerAndCleanups attaches a finalizer and n cleanups to a new object,
+// drops it, and returns the events in the order they ran: "finalizer" and
+// "cleanup 1" through "cleanup n".
+func finalizerAndCleanups(n int) ([]string, error) {
+	type resource struct {
+		name string
+		id   int
+	}
+	events := make(chan string, n+1)
+	r := &resource{name: "conn", id: 1}
+	runtime.SetFinalizer(r, func(r *resource) { events <- "finalizer" })
+	for i := range n {
+		runtime.AddCleanup(r, func(n int) { events <- fmt.Sprintf("cleanup %d", n) }, i+1)
+	}
+	r = nil
+
+	var order []string
+	deadline := time.After(2 * time.Second)
+	for len(order) < n+1 {
+		runtime.GC()
+		select {
+		case e := <-events:
+			order = append(order, e)
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			return order, fmt.Errorf("timed out waiting for finalizer and cleanups, got: %v", order)
+		}
+	}
+	return order, nil
+}
+
+func DemoCleanupVsFinalizer(w io.Writer) error {
+	order, err := finalizerAndCleanups(2)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Finalizer and cleanups ran in order:", order)
+	// Cleanups for the same object may run in any order.
+	cleanups := slices.Sorted(slices.Values(order[1:]))
+	if order[0] != "finalizer" || !slices.Equal(cleanups, []string{"cleanup 1", "cleanup 2"}) {
+		return fmt.Errorf("got events %v, want the finalizer first and then each cleanup once", order)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 72. encoding/json: Streaming JSON Lines into an os.Root file
+//
+// A json.Encoder writes one value per line, which is exactly the JSON Lines
+// format. Encode validates a value before writing anything, so a value that
+// cannot be encoded leaves the stream intact and the caller can decide
+// whether to skip it or stop. Reading back, strings.Lines yields each record.
+func DemoJSONToFile(w io.Writer) error {
+	type reading struct {
+		Sensor string  `json:"sensor"`
+		Value  float64 `json:"value"`
+	}
+	dir, err := os.MkdirTemp("", "demo-jsonl")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+
+	f, err := root.Create("readings.jsonl")
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	records := []reading{
+		{"temp", 21.5},
+		{"humidity", 0.43},
+		{"temp", math.NaN()}, // JSON has no NaN
+		{"pressure", 1013.25},
+	}
+	enc := json.NewEncoder(f)
+	written := 0
+	for i, r := range records {
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(w, "Skipping record %d: %v\n", i, err)
+			continue
+		}
+		written++
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	data, err := fs.ReadFile(root.FS(), "readings.jsonl")
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	var decoded []reading
+	for line := range strings.Lines(string(data)) {
+		var r reading
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+		decoded = append(decoded, r)
+	}
+	// Only the NaN record fails to encode.
+	if written != len(records)-1 || len(decoded) != written {
+		return fmt.Errorf("wrote %d of %d records and read back %d, want %d of each", written, len(records), len(decoded), len(records)-1)
+	}
+	fmt.Fprintf(w, "Wrote %d of %d records, read back %d: %v\n", written, len(records), len(decoded), decoded)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 73. os.Root: Reading user-supplied paths safely
+//
+// A server that maps request paths onto a directory must not let "../",
+// absolute paths, or symlinks reach files outside it. filepath.IsLocal
+// rejects the first two lexically; os.Root also catches symlinks, which
+// cannot be detected by looking at the path alone.
+
+// ErrPathTraversal is returned, wrapped, by SafeReadFile when a path leads
+// outside the root directory.
+var ErrPathTraversal = errors.New("path escapes root directory")
+
+// SafeReadFile reads relPath within rootDir. The read fails with an error
+// wrapping ErrPathTraversal if relPath is absolute, climbs out with "..", or
+// resolves through a symlink to a file outside rootDir.
+func SafeReadFile(rootDir, relPath string) ([]byte, error) {
+	if !filepath.IsLocal(relPath) {
+		return nil, fmt.Errorf("reading %q: %w", relPath, ErrPathTraversal)
+	}
+	root, err := os.OpenRoot(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	f, err := root.Open(relPath)
+	if err != nil {
+		if escapesRoot(rootDir, relPath) {
+			return nil, fmt.Errorf("reading %q: %w: %w", relPath, ErrPathTraversal, err)
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// escapesRoot reports whether relPath, with symlinks resolved, names a file
+// outside rootDir. os.Root reports escapes with an unexported error, so this
+// only classifies an error the root has already returned; the root is what
+// enforces the boundary. A dangling symlink cannot be resolved and is not
+// classified.
+func escapesRoot(rootDir, relPath string) bool {
+	base, err := filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		return false
+	}
+	target, err := filepath.EvalSymlinks(filepath.Join(rootDir, relPath))
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(base, target)
+	return err == nil && !filepath.IsLocal(rel)
+}
+
+func DemoSafeReadFile(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-safe-read")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	public := filepath.Join(dir, "public")
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.MkdirAll(filepath.Join(public, "docs"), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	for name, data := range map[string]string{
+		secret: "do not serve",
+		filepath.Join(public, "docs", "index.txt"): "welcome",
+	} {
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			return fmt.Errorf("writing file: %w", err)
+		}
+	}
+	// A symlink that stays inside the root is fine; one that points out is not.
+	links := map[string]string{
+		"home.txt": filepath.Join("docs", "index.txt"),
+		"leak.txt": secret,
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(public, name)); err != nil {
+			fmt.Fprintln(w, "Symlinks unsupported, skipping demo:", err)
+			return nil
+		}
+	}
+
+	for _, p := range []string{"docs/index.txt", "home.txt", "../secret.txt", "docs/../../secret.txt", secret, "leak.txt"} {
+		data, err := SafeReadFile(public, p)
+		if errors.Is(err, ErrPathTraversal) {
+			fmt.Fprintf(w, "SafeReadFile(%q): refused: %v\n", p, err)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("SafeReadFile(%q): %w", p, err)
+		}
+		fmt.Fprintf(w, "SafeReadFile(%q): %q\n", p, data)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 74. math/big: Text, Append, and AppendText in different bases
+//
+// AppendText always produces base 10, the encoding used by JSON and other
+// text formats. Text and Append take an explicit base from 2 to 62; digits
+// past 9 are a-z and then A-Z. SetString parses any of them back; like Text,
+// it panics on a base outside that range rather than returning false.
+func DemoBigBase(w io.Writer) error {
+	n, ok := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	if !ok {
+		return errors.New("big.Int: invalid literal")
+	}
+	text, err := n.AppendText([]byte("base 10 (AppendText): "))
+	if err != nil {
+		return fmt.Errorf("AppendText: %w", err)
+	}
+	fmt.Fprintln(w, string(text))
+
+	for _, base := range []int{2, 16, 36, 62} {
+		buf := n.Append(nil, base)
+		back, ok := new(big.Int).SetString(string(buf), base)
+		if !ok || back.Cmp(n) != 0 {
+			return fmt.Errorf("base %d: %s did not round trip", base, buf)
+		}
+		fmt.Fprintf(w, "base %-2d: %s\n", base, buf)
+	}
+	if n.Text(16) != string(n.Append(nil, 16)) {
+		return errors.New("big.Int Text and Append disagree")
+	}
+	fmt.Fprintln(w, "Supported bases: 2 through", big.MaxBase)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 75. Generics and timers: Debouncing a channel
+//
+// Debounce coalesces bursts: each value restarts a quiet-period timer, and
+// only the last value of a burst is forwarded once the input has been quiet
+// that long. Since Go 1.23, Reset on an unstopped timer never delivers a
+// stale expiry, so a single timer can be reused for every value.
+
+// Debounce forwards the last value received from in once no further value
+// has arrived for quiet. When in is closed any pending value is flushed and
+// the returned channel is closed.
+func Debounce[T any](in <-chan T, quiet time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(quiet)
+		timer.Stop()
+		var pending T
+		var have bool
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if have {
+						out <- pending
+					}
+					return
+				}
+				pending, have = v, true
+				timer.Reset(quiet)
+			case <-timer.C:
+				out <- pending
+				have = false
+			}
+		}
+	}()
+	return out
+}
+
+func DemoDebounce(w io.Writer) error {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		// Two back-to-back bursts separated by a gap well over the quiet
+		// period. TestDebounce checks exact timings with testing/synctest.
+		for _, burst := range [][]int{{1, 2, 3, 4, 5}, {6, 7, 8}} {
+			for _, v := range burst {
+				in <- v
+			}
+			time.Sleep(150 * time.Millisecond)
+		}
+	}()
+	var got []int
+	for v := range Debounce(in, 50*time.Millisecond) {
+		got = append(got, v)
+	}
+	if want := []int{5, 8}; !slices.Equal(got, want) {
+		return fmt.Errorf("debounced two bursts to %v, want %v", got, want)
+	}
+	fmt.Fprintln(w, "Debounced 8 inputs in two bursts to:", got)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 76. strings.Lines and strings.Cut: Parsing HTTP-style headers
+//
+// A header block is a natural fit for strings.Lines: each "Key: Value" line
+// splits with strings.Cut, a line starting with a space or tab continues the
+// previous value, and repeated keys accumulate, just as net/textproto does.
+
+// parseHeaders parses "Key: Value" lines into a map keyed by canonical MIME
+// header key. Parsing stops at the first blank line.
+func parseHeaders(block string) (map[string][]string, error) {
+	headers := make(map[string][]string)
+	var last string
+	n := 0
+	for line := range strings.Lines(block) {
+		n++
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if last == "" {
+				return nil, fmt.Errorf("line %d: continuation before first header", n)
+			}
+			vals := headers[last]
+			vals[len(vals)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing colon in %q", n, line)
+		}
+		last = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(key))
+		headers[last] = append(headers[last], strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+func DemoHeaderParse(w io.Writer) error {
+	block := "Content-Type: text/plain\r\n" +
+		"x-trace-id: abc123\r\n" +
+		"Accept: text/html\r\n" +
+		"accept: application/json\r\n" +
+		"X-Long: first part\r\n" +
+		"\tsecond part\r\n" +
+		"\r\n"
+	headers, err := parseHeaders(block)
+	if err != nil {
+		return fmt.Errorf("header parse: %w", err)
+	}
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		fmt.Fprintf(w, "  %s: %q\n", key, headers[key])
+	}
+
+	// textproto.Reader implements the same rules.
+	want, err := textproto.NewReader(bufio.NewReader(strings.NewReader(block))).ReadMIMEHeader()
+	if err != nil {
+		return fmt.Errorf("textproto: %w", err)
+	}
+	if !maps.EqualFunc(headers, want, slices.Equal) {
+		return fmt.Errorf("parseHeaders gave %v, textproto.ReadMIMEHeader gave %v", headers, want)
+	}
+	fmt.Fprintln(w, "Matches textproto.ReadMIMEHeader")
+
+	if _, err := parseHeaders("Host: example.com\r\nno colon here\r\n"); err != nil {
+		fmt.Fprintln(w, "Malformed header rejected:", err)
+	} else {
+		return errors.New("malformed header unexpectedly parsed")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 77. crypto: Selecting a hash by name
+//
+// Configuration files and wire protocols name hashes as strings. A table of
+// constructors keeps the choice pluggable; the SHA-3 entries reuse
+// NewSHA3Hasher so the sizes stay validated in one place.
+
+var hashesByName = map[string]func() hash.Hash{
+	"sha224":   sha256.New224,
+	"sha256":   sha256.New,
+	"sha384":   sha512.New384,
+	"sha512":   sha512.New,
+	"sha3-256": func() hash.Hash { h, _ := NewSHA3Hasher(256); return h },
+	"sha3-512": func() hash.Hash { h, _ := NewSHA3Hasher(512); return h },
+}
+
+// NewHashByName returns a new hash for one of the names in hashesByName.
+func NewHashByName(name string) (hash.Hash, error) {
+	newHash, ok := hashesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash %q", name)
+	}
+	return newHash(), nil
+}
+
+func DemoHashFamily(w io.Writer) error {
+	input := []byte("The quick brown fox jumps over the lazy dog")
+	for _, name := range slices.Sorted(maps.Keys(hashesByName)) {
+		h, err := NewHashByName(name)
+		if err != nil {
+			return fmt.Errorf("hash: %w", err)
+		}
+		h.Write(input)
+		fmt.Fprintf(w, "%-8s (%2d bytes): %x\n", name, h.Size(), h.Sum(nil))
+	}
+	if _, err := NewHashByName("md5"); err != nil {
+		fmt.Fprintln(w, "NewHashByName rejects unknown names:", err)
+	} else {
+		return errors.New("NewHashByName accepted md5")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 78. Iterators: Collecting from an unbounded sequence
+//
+// slices.Collect drains its argument, so on an infinite sequence like Primes
+// it never returns. Take bounds the sequence first; once it has yielded n
+// values it stops ranging, which in turn stops the underlying generator.
+
+// Take yields at most the first n values of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+func DemoCollectLimited(w io.Writer) error {
+	fmt.Fprintln(w, "First 10 primes:", slices.Collect(Take(Primes(), 10)))
+	none := slices.Collect(Take(Primes(), 0))
+	fmt.Fprintf(w, "Take(Primes(), 0) collects %d values (nil: %t)\n", len(none), none == nil)
+	fmt.Fprintln(w, "Take(5) of a 3-element sequence:", slices.Collect(Take(slices.Values([]int{1, 2, 3}), 5)))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 79. time: Converting one instant across zones
+//
+// Time.In changes only the location used for display; the instant is the
+// same, so the converted values compare Equal. AppendFormat writes each one
+// into a shared buffer. The time/tzdata import embeds the zone database so
+// LoadLocation works even on systems without one.
+func DemoZoneConvert(w io.Writer) error {
+	instant := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	names := []string{"America/New_York", "Europe/London", "Asia/Kolkata", "Australia/Sydney"}
+
+	const layout = "MST 3:04PM Jan 2"
+	buf := instant.AppendFormat(nil, layout)
+	for _, name := range names {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("LoadLocation: %w", err)
+		}
+		local := instant.In(loc)
+		if !local.Equal(instant) {
+			return fmt.Errorf("Time.In changed the instant for %s", name)
+		}
+		buf = append(buf, " | "...)
+		buf = local.AppendFormat(buf, layout)
+	}
+	fmt.Fprintln(w, string(buf))
+
+	// Kolkata keeps the same offset all year; New York moves with DST.
+	for _, name := range []string{"Asia/Kolkata", "America/New_York"} {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("LoadLocation: %w", err)
+		}
+		winter := time.Date(2025, time.January, 1, 12, 0, 0, 0, loc)
+		summer := winter.AddDate(0, 6, 0)
+		_, winterOffset := winter.Zone()
+		_, summerOffset := summer.Zone()
+		fmt.Fprintf(w, "%s: offset January %+gh, July %+gh, DST in July: %t\n",
+			name, float64(winterOffset)/3600, float64(summerOffset)/3600, summer.IsDST())
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 80. runtime.AddCleanup: Releasing forgotten handles
+//
+// A handle registers a cleanup when it is acquired, so a caller that forgets
+// to Close it still releases the underlying resource once the handle is
+// garbage collected. Close releases eagerly and stops the cleanup, so the
+// resource is released exactly once either way.
+
+// ResourceHandle owns a fake file descriptor until it is closed or
+// collected.
+type ResourceHandle struct {
+	fd      int
+	release func(fd int)
+	cleanup runtime.Cleanup
+	closed  bool
+}
+
+// AcquireResource returns a handle for resource id. release stands in for
+// closing a file descriptor and is called with it once, by Close or by a
+// cleanup after the handle is collected. The cleanup receives release and
+// the descriptor by value, not the handle, so it does not keep the handle
+// alive.
+func AcquireResource(id int, release func(fd int)) *ResourceHandle {
+	h := &ResourceHandle{fd: id, release: release}
+	h.cleanup = runtime.AddCleanup(h, release, id)
+	return h
+}
+
+// Close releases the resource now instead of waiting for the GC. It is safe
+// to call more than once.
+func (h *ResourceHandle) Close() {
+	if h.closed {
+		return
+	}
+	h.closed = true
+	h.cleanup.Stop()
+	h.release(h.fd)
+}
+
+func DemoResourceHandle(w io.Writer) error {
+	released := make(chan int, 4)
+	release := func(fd int) {
+		fmt.Fprintln(w, "Released fd", fd)
+		released <- fd
+	}
+	// waitRelease runs the GC until a release happens, or gives up.
+	waitRelease := func() (int, bool) {
+		for range 100 {
+			runtime.GC()
+			select {
+			case fd := <-released:
+				return fd, true
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		return 0, false
+	}
+	// settle runs a few GC cycles to give any stray cleanup the chance to run.
+	settle := func() {
+		for range 3 {
+			runtime.GC()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	fmt.Fprintln(w, "Closing handle 1 explicitly, then dropping it:")
+	h := AcquireResource(1, release)
+	h.Close()
+	h.Close()
+	h = nil
+	settle()
+	if n := len(released); n != 1 {
+		return fmt.Errorf("handle 1 was released %d times after Close, Close, and GC, want 1", n)
+	}
+	<-released
+
+	fmt.Fprintln(w, "Dropping handle 2 without closing it:")
+	AcquireResource(2, release)
+	fd, ok := waitRelease()
+	if !ok {
+		return errors.New("timed out waiting for the cleanup")
+	}
+	if fd != 2 {
+		return fmt.Errorf("cleanup released fd %d, want 2", fd)
+	}
+	settle()
+	if n := len(released); n != 0 {
+		return fmt.Errorf("handle 2 was released %d more times after its cleanup", n)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 81. Generics and sync.Pool: A typed pool
+//
+// sync.Pool traffics in any, so every Get needs a type assertion and nothing
+// stops a caller from putting back the wrong type. Pool[T] wraps it with a
+// typed API and resets values on Put, so a buffer never comes back holding
+// the previous user's data.
+
+// Pool is a typed wrapper around sync.Pool.
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(T)
+}
+
+// NewPool returns a pool that creates values with newValue and passes each
+// value to reset when it is returned with Put.
+func NewPool[T any](newValue func() T, reset func(T)) *Pool[T] {
+	return &Pool[T]{
+		pool:  sync.Pool{New: func() any { return newValue() }},
+		reset: reset,
+	}
+}
+
+// Get returns a value from the pool, creating one if the pool is empty.
+func (p *Pool[T]) Get() T {
+	return p.pool.Get().(T)
+}
+
+// Put resets v and returns it to the pool.
+func (p *Pool[T]) Put(v T) {
+	p.reset(v)
+	p.pool.Put(v)
+}
+
+func DemoTypedPool(w io.Writer) error {
+	buffers := NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+
+	buf := buffers.Get()
+	buf.WriteString("left over from the last request")
+	buffers.Put(buf)
+	buf = buffers.Get()
+	if buf.Len() != 0 {
+		return fmt.Errorf("pooled buffer kept %q after Put", buf.String())
+	}
+	fmt.Fprintf(w, "Buffer after Put and Get: len=%d cap>0=%t\n", buf.Len(), buf.Cap() > 0)
+
+	// Appending into a pooled buffer reuses its storage; BenchmarkPool
+	// measures the allocations this saves.
+	buf.Write(big.NewInt(1<<62).Append(buf.AvailableBuffer(), 16))
+	buf.WriteString(" rendered")
+	fmt.Fprintln(w, "Rendered into a pooled buffer:", buf.String())
+	buffers.Put(buf)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 82. encoding/json: Valid, Compact, and Indent
+//
+// These work on raw bytes without decoding into Go values, so they preserve
+// key order, duplicate keys, and number precision exactly as written. Compact
+// and Indent validate as they go and leave dst unchanged on error.
+func DemoJSONTransform(w io.Writer) error {
+	input := []byte(`{
+	  "name":   "gopher",
+	  "tags": [ "go",   "1.24" ],
+	  "id": 12345678901234567890
+	}`)
+	fmt.Fprintln(w, "Valid:", json.Valid(input))
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, input); err != nil {
+		return fmt.Errorf("compact: %w", err)
+	}
+	fmt.Fprintln(w, "Compact:", compact.String())
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return fmt.Errorf("indent: %w", err)
+	}
+	fmt.Fprintln(w, "Indent:")
+	fmt.Fprintln(w, indented.String())
+
+	invalid := []byte(`{"name": "gopher",}`)
+	if json.Valid(invalid) {
+		return fmt.Errorf("json.Valid accepted %s", invalid)
+	}
+	fmt.Fprintln(w, "Valid (trailing comma): false")
+	compact.Reset()
+	if err := json.Compact(&compact, invalid); err != nil {
+		fmt.Fprintf(w, "Compact rejected it: %v (wrote %d bytes)\n", err, compact.Len())
+	} else {
+		return errors.New("json.Compact unexpectedly accepted a trailing comma")
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 83. os.Root: Classifying errors
+//
+// Errors from os.Root methods are *fs.PathError values, like those from the
+// os package, so errors.Is against fs.ErrNotExist, fs.ErrExist, and
+// fs.ErrPermission works as usual. For errors from Root methods,
+// PathError.Path holds the name as passed to the root, relative to it. A file
+// opened through the root is named by its full host path, though, and errors
+// from its own methods report that path.
+func DemoRootErrors(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-root-errors")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+	if err := root.Mkdir("sub", 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := root.Mkdir("locked", 0o000); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	classify := func(op string, err error) {
+		if err == nil {
+			fmt.Fprintf(w, "  %-26s succeeded\n", op)
+			return
+		}
+		kind := "other"
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			kind = "fs.ErrNotExist"
+		case errors.Is(err, fs.ErrExist):
+			kind = "fs.ErrExist"
+		case errors.Is(err, fs.ErrPermission):
+			kind = "fs.ErrPermission"
+		}
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			fmt.Fprintf(w, "  %-26s %-16s op=%s path=%q err=%v\n", op, kind, pathErr.Op, pathErr.Path, pathErr.Err)
+		} else {
+			fmt.Fprintf(w, "  %-26s %-16s %v\n", op, kind, err)
+		}
+	}
+
+	fmt.Fprintln(w, "os.Root error classification:")
+	_, err = root.Open("sub/missing.txt")
+	classify("Open sub/missing.txt", err)
+	classify("Mkdir sub", root.Mkdir("sub", 0o755))
+	_, err = root.Open("../escape.txt")
+	classify("Open ../escape.txt", err)
+	_, err = root.Open("locked/file.txt")
+	classify("Open locked/file.txt", err)
+	if os.Geteuid() == 0 {
+		fmt.Fprintln(w, "  (running as root: permission checks are bypassed, so locked/ is searchable)")
+	}
+	// Unlocking must work without read access to the directory.
+	if err := rootChmod(root, "locked", 0o755); err != nil {
+		return fmt.Errorf("unlocking directory: %w", err)
+	}
+
+	// Opening a directory succeeds; reading it as a file fails.
+	d, err := root.Open("sub")
+	if err != nil {
+		return fmt.Errorf("opening directory: %w", err)
+	}
+	_, err = d.Read(make([]byte, 1))
+	d.Close()
+	classify("Read from directory sub", err)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 84. Generics and iterators: A prefix trie
+//
+// A trie stores keys by their bytes, one edge per byte, so every key sharing
+// a prefix lives under the same node. PrefixMatch walks down to that node and
+// returns an iterator over the subtree in lexicographic order, which is what
+// autocomplete wants; stopping the range early stops the walk.
+
+type trieNode[V any] struct {
+	children map[byte]*trieNode[V]
+	value    V
+	ok       bool
+}
+
+// Trie maps string keys to values of type V. The zero value is an empty trie.
+type Trie[V any] struct {
+	root trieNode[V]
+	size int
+}
+
+// Insert sets the value for key, replacing any existing value.
+func (t *Trie[V]) Insert(key string, value V) {
+	n := &t.root
+	for i := range len(key) {
+		if n.children == nil {
+			n.children = make(map[byte]*trieNode[V])
+		}
+		child, ok := n.children[key[i]]
+		if !ok {
+			child = new(trieNode[V])
+			n.children[key[i]] = child
+		}
+		n = child
+	}
+	if !n.ok {
+		t.size++
+	}
+	n.value, n.ok = value, true
+}
+
+// find returns the node for key, or nil if no key has that prefix.
+func (t *Trie[V]) find(key string) *trieNode[V] {
+	n := &t.root
+	for i := range len(key) {
+		if n = n.children[key[i]]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Get returns the value stored for key and whether it was present.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	if n := t.find(key); n != nil && n.ok {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Len returns the number of keys in the trie.
+func (t *Trie[V]) Len() int { return t.size }
+
+// PrefixMatch returns an iterator over the keys starting with prefix and
+// their values, in lexicographic order. An empty prefix matches every key.
+func (t *Trie[V]) PrefixMatch(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := t.find(prefix)
+		if n == nil {
+			return
+		}
+		var walk func(n *trieNode[V], key []byte) bool
+		walk = func(n *trieNode[V], key []byte) bool {
+			if n.ok && !yield(string(key), n.value) {
+				return false
+			}
+			for _, b := range slices.Sorted(maps.Keys(n.children)) {
+				if !walk(n.children[b], append(key, b)) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(n, []byte(prefix))
+	}
+}
+
+func DemoTrie(w io.Writer) error {
+	var commands Trie[string]
+	for cmd, desc := range map[string]string{
+		"go build": "compile packages",
+		"go bug":   "start a bug report",
+		"go test":  "test packages",
+		"go tool":  "run a specified tool",
+		"go vet":   "report likely mistakes",
+		"gofmt":    "format Go source",
+		"git":      "not a go command",
+		"go":       "the go command itself",
+	} {
+		commands.Insert(cmd, desc)
+	}
+	commands.Insert("go vet", "report likely mistakes in packages") // replaces
+
+	for _, prefix := range []string{"go t", "go b", "gox"} {
+		fmt.Fprintf(w, "Completions for %q:", prefix)
+		for cmd := range commands.PrefixMatch(prefix) {
+			fmt.Fprintf(w, " [%s]", cmd)
+		}
+		fmt.Fprintln(w)
+	}
+	if desc, ok := commands.Get("go vet"); ok {
+		fmt.Fprintln(w, "Get(\"go vet\"):", desc)
+	}
+	if _, ok := commands.Get("go t"); !ok {
+		fmt.Fprintln(w, "Get(\"go t\"): not a key, only a prefix")
+	}
+
+	all := 0
+	for range commands.PrefixMatch("") {
+		all++
+	}
+	fmt.Fprintf(w, "Empty prefix yields all %d keys (Len %d)\n", all, commands.Len())
+	first := ""
+	for cmd := range commands.PrefixMatch("") {
+		first = cmd
+		break
+	}
+	fmt.Fprintln(w, "First key in order, stopping early:", first)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 85. crypto/subtle: Constant-time selection
+//
+// Code that branches or returns early on secret data runs for a
+// data-dependent time, and an attacker who can measure that time learns
+// about the secret. ConstantTimeSelect and ConstantTimeByteEq compute their
+// results with arithmetic instead of branches, so a lookup built from them
+// touches every entry and takes the same time whichever one matches.
+
+// constantTimeLookup returns the value paired with the secret key in keys,
+// or 0 if none matches, scanning the whole table without branching on key.
+func constantTimeLookup(keys []uint8, values []int, key uint8) int {
+	result := 0
+	for i, k := range keys {
+		result = subtle.ConstantTimeSelect(subtle.ConstantTimeByteEq(k, key), values[i], result)
+	}
+	return result
+}
+
+func DemoConstantTimeSelect(w io.Writer) error {
+	fmt.Fprintln(w, "ConstantTimeSelect(1, 10, 20) =", subtle.ConstantTimeSelect(1, 10, 20))
+	fmt.Fprintln(w, "ConstantTimeSelect(0, 10, 20) =", subtle.ConstantTimeSelect(0, 10, 20))
+	fmt.Fprintln(w, "ConstantTimeByteEq(7, 7) =", subtle.ConstantTimeByteEq(7, 7), "ConstantTimeByteEq(7, 8) =", subtle.ConstantTimeByteEq(7, 8))
+
+	keys := []uint8{0x10, 0x20, 0x30, 0x40}
+	values := []int{1000, 2000, 3000, 4000}
+	for _, key := range []uint8{0x30, 0x10, 0x99} {
+		fmt.Fprintf(w, "constantTimeLookup(%#x) = %d\n", key, constantTimeLookup(keys, values, key))
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 86. runtime.Stack: Capturing a goroutine dump
+//
+// runtime.Stack(buf, true) writes the stacks of all goroutines into buf and
+// returns the number of bytes written, silently truncating if buf is too
+// small. A result that fills buf exactly may have been cut off, so the
+// buffer is doubled until the dump fits.
+
+// allStacks returns the stacks of all goroutines, starting from a buffer of
+// size bytes and growing it as needed. It also reports how many times the
+// buffer had to grow.
+func allStacks(size int) ([]byte, int) {
+	grows := 0
+	for {
+		buf := make([]byte, size)
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n], grows
+		}
+		size *= 2
+		grows++
+	}
+}
+
+func DemoStackDump(w io.Writer) error {
+	stop := make(chan struct{})
+	for range 3 {
+		go func() { <-stop }()
+	}
+	defer close(stop)
+
+	dump, grows := allStacks(256)
+	goroutines, ours := 0, 0
+	for line := range strings.Lines(string(dump)) {
+		if strings.HasPrefix(line, "goroutine ") {
+			goroutines++
+		}
+		if strings.HasPrefix(line, "created by main.DemoStackDump") {
+			ours++
+		}
+	}
+	fmt.Fprintf(w, "Stack dump: %d bytes after %d grows, %d goroutines, %d started by this demo\n",
+		len(dump), grows, goroutines, ours)
+	first, _, _ := bytes.Cut(dump, []byte("\n"))
+	fmt.Fprintf(w, "First line: %s\n", first)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 87. net/http: Serving an os.Root with FileServerFS
+//
+// http.FileServerFS serves any fs.FS. Backing it with Root.FS means a
+// request can only reach files inside the root: ".." segments are cleaned
+// away before the lookup, so they find nothing and get a 404, and a symlink
+// that leads out of the root fails to open instead of being followed. That
+// error is neither fs.ErrNotExist nor fs.ErrPermission, so it surfaces as a
+// 500.
+func DemoFileServerFS(w io.Writer) error {
+	dir, err := os.MkdirTemp("", "demo-fileserver")
+	if err != nil {
+		return fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	public := filepath.Join(dir, "public")
+	if err := os.Mkdir(public, 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("do not serve"), 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(public, "hello.txt"), []byte("hello over HTTP"), 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(public, "leak.txt")); err != nil {
+		fmt.Fprintln(w, "Symlinks unsupported, skipping demo:", err)
+		return nil
+	}
+
+	root, err := os.OpenRoot(public)
+	if err != nil {
+		return fmt.Errorf("opening root: %w", err)
+	}
+	defer root.Close()
+	srv := httptest.NewServer(http.FileServerFS(root.FS()))
+	defer srv.Close()
+
+	for _, path := range []string{"/hello.txt", "/../secret.txt", "/%2e%2e/secret.txt", "/leak.txt"} {
+		resp, err := srv.Client().Get(srv.URL + path)
+		if err != nil {
+			return fmt.Errorf("request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("reading body: %w", err)
+		}
+		fmt.Fprintf(w, "GET %-18s %s %q\n", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 88. slices and cmp: Deterministic multi-key sorting
+//
+// slices.SortFunc is not stable, so records that compare equal may come out
+// in any order. cmp.Or returns its first non-zero argument, which turns a
+// list of per-key comparisons into one comparison that only reports equal
+// when every key is equal. With a unique final key the order is fully
+// determined, no stable sort required.
+func DemoMultiKeySort(w io.Writer) error {
+	type employee struct {
+		Team string
+		Name string
+		Age  int
+	}
+	byTeamThenName := func(a, b employee) int {
+		return cmp.Or(
+			cmp.Compare(a.Team, b.Team),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Age, b.Age),
+		)
+	}
+
+	staff := []employee{
+		{"runtime", "Dave", 40},
+		{"crypto", "Carol", 35},
+		{"runtime", "Alice", 38},
+		{"crypto", "Erin", 31},
+		{"runtime", "Alice", 29},
+	}
+	slices.SortFunc(staff, byTeamThenName)
+	fmt.Fprintln(w, "Sorted by team, then name, then age:")
+	for _, e := range staff {
+		fmt.Fprintf(w, "  %-8s %-8s %d\n", e.Team, e.Name, e.Age)
+	}
+
+	// With every primary key equal, the order is decided by the tiebreakers
+	// alone, whatever order the input arrives in.
+	same := []employee{{"tools", "Bob", 60}, {"tools", "Alice", 30}, {"tools", "Bob", 55}}
+	reversed := slices.Clone(same)
+	slices.Reverse(reversed)
+	slices.SortFunc(same, byTeamThenName)
+	slices.SortFunc(reversed, byTeamThenName)
+	fmt.Fprintln(w, "All-equal primary keys:", same, "same order from reversed input:", slices.Equal(same, reversed))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 89. Generics and channels: A future
+//
+// A Future holds a value that will be produced later. Closing a channel is
+// a broadcast, so any number of goroutines can wait in Get, and once it is
+// closed every later Get returns at once. sync.Once makes Set safe to call
+// from racing producers: the first value wins.
+
+// Future is a value of type T that is set once and read many times.
+type Future[T any] struct {
+	once  sync.Once
+	done  chan struct{}
+	value T
+}
+
+// NewFuture returns an unresolved future.
+func NewFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Set resolves the future with v. Only the first call has any effect; it
+// reports whether this call was the one that resolved the future.
+func (f *Future[T]) Set(v T) bool {
+	set := false
+	f.once.Do(func() {
+		f.value = v
+		close(f.done)
+		set = true
+	})
+	return set
+}
+
+// Get waits for the future to be resolved and returns its value, or returns
+// ctx's error if ctx is done first.
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func DemoFuture(w io.Writer) error {
+	answer := NewFuture[int]()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		answer.Set(42)
+	}()
+	v, err := answer.Get(context.Background())
+	if err != nil || v != 42 {
+		return fmt.Errorf("resolved future: got %d, %v; want 42", v, err)
+	}
+	fmt.Fprintln(w, "Resolved future:", v)
+
+	// A resolved future answers at once, and keeps its first value.
+	v, err = answer.Get(context.Background())
+	if err != nil || v != 42 {
+		return fmt.Errorf("Get after Set: got %d, %v; want 42", v, err)
+	}
+	if answer.Set(7) {
+		return errors.New("second Set was accepted")
+	}
+	fmt.Fprintln(w, "Get after Set:", v, "(second Set ignored)")
+
+	never := NewFuture[string]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := never.Get(ctx); errors.Is(err, context.DeadlineExceeded) {
+		fmt.Fprintln(w, "Canceled future:", err)
+	} else {
+		return fmt.Errorf("unresolved future: want a deadline error, got %v", err)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 90. encoding/json: Enforcing invariants in UnmarshalJSON
+//
+// A type that validates itself in UnmarshalJSON cannot be decoded into an
+// invalid state, so code holding one never needs to re-check it. The decoder
+// wraps the method's error with no extra context, so the error itself
+// should name the offending value.
+
+// Rating is a review score from 1 to 5 stars.
+type Rating int
+
+// UnmarshalJSON implements json.Unmarshaler, rejecting ratings outside 1-5.
+func (r *Rating) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("rating: %w", err)
+	}
+	if n < 1 || n > 5 {
+		return fmt.Errorf("rating %d out of range 1-5", n)
+	}
+	*r = Rating(n)
+	return nil
+}
+
+func DemoValidatingUnmarshal(w io.Writer) error {
+	type review struct {
+		Product string `json:"product"`
+		Stars   Rating `json:"stars"`
+	}
+	for _, tt := range []struct {
+		input  string
+		reject bool
+	}{
+		{`{"product": "gopher plush", "stars": 5}`, false},
+		{`{"product": "gopher mug", "stars": 11}`, true},
+		{`{"product": "gopher mug", "stars": "five"}`, true},
+	} {
+		var r review
+		err := json.Unmarshal([]byte(tt.input), &r)
+		switch {
+		case err != nil && tt.reject:
+			fmt.Fprintf(w, "Rejected %s: %v\n", tt.input, err)
+		case err != nil:
+			return fmt.Errorf("decoding %s: %w", tt.input, err)
+		case tt.reject:
+			return fmt.Errorf("%s unexpectedly decoded as %+v", tt.input, r)
+		default:
+			fmt.Fprintf(w, "Decoded %s: %+v\n", tt.input, r)
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 91. math/bits: A tiny hash mixer
+//
+// Rotations and byte reversal move bits without losing any, so they are
+// cheap, invertible building blocks for mixing; a multiply and xor-shift then
+// spread each input bit across the output. OnesCount and Len show how much a
+// step changed. Every step maps zero to zero, so a real hash adds a seed.
+
+// mixBits scrambles x with rotations, a byte swap, and a multiply. If trace
+// is not nil it is called with the value after each step.
+func mixBits(x uint64, trace func(step string, v uint64)) uint64 {
+	if trace == nil {
+		trace = func(string, uint64) {}
+	}
+	hi, lo := uint32(x>>32), uint32(x)
+	hi = bits.RotateLeft32(hi, 13) ^ lo
+	lo = bits.RotateLeft32(lo, -7) + hi
+	x = uint64(hi)<<32 | uint64(lo)
+	trace("rotate halves", x)
+	x = bits.ReverseBytes64(x)
+	trace("reverse bytes", x)
+	x *= 0x9e3779b97f4a7c15
+	trace("multiply", x)
+	x ^= x >> 29
+	trace("xor-shift", x)
+	return x
+}
+
+func DemoMathBits(w io.Writer) error {
+	for _, x := range []uint64{0, 1, math.MaxUint64} {
+		fmt.Fprintf(w, "mixBits(%#x):\n", x)
+		mixBits(x, func(step string, v uint64) {
+			fmt.Fprintf(w, "  %-13s %016x ones=%2d len=%2d\n", step, v, bits.OnesCount64(v), bits.Len64(v))
+		})
+	}
+	// Neighbouring inputs should differ in about half of their output bits.
+	diff := mixBits(1, nil) ^ mixBits(2, nil)
+	fmt.Fprintf(w, "mixBits(1) and mixBits(2) differ in %d of 64 bits\n", bits.OnesCount64(diff))
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 92. Generics and iterators: Graph traversal
+//
+// BFS and DFS return iterators rather than slices, so a caller searching for
+// one node can stop the traversal as soon as it is found. Both visit only
+// the nodes reachable from start; a node with no path from it never appears.
+
+// Graph is a directed graph over nodes of type T, stored as adjacency lists.
+// The zero value is an empty graph.
+type Graph[T comparable] struct {
+	edges map[T][]T
+}
+
+// AddNode adds n to the graph if it is not already present.
+func (g *Graph[T]) AddNode(n T) {
+	if g.edges == nil {
+		g.edges = make(map[T][]T)
+	}
+	if _, ok := g.edges[n]; !ok {
+		g.edges[n] = nil
+	}
+}
+
+// AddEdge adds an edge from one node to another, adding either node as
+// needed.
+func (g *Graph[T]) AddEdge(from, to T) {
+	g.AddNode(from)
+	g.AddNode(to)
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// BFS yields the nodes reachable from start in breadth-first order.
+func (g *Graph[T]) BFS(start T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if _, ok := g.edges[start]; !ok {
+			return
+		}
+		seen := map[T]bool{start: true}
+		queue := []T{start}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+			for _, next := range g.edges[n] {
+				if !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+}
+
+// DFS yields the nodes reachable from start in depth-first preorder.
+func (g *Graph[T]) DFS(start T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if _, ok := g.edges[start]; !ok {
+			return
+		}
+		seen := make(map[T]bool)
+		var visit func(n T) bool
+		visit = func(n T) bool {
+			seen[n] = true
+			if !yield(n) {
+				return false
+			}
+			for _, next := range g.edges[n] {
+				if !seen[next] && !visit(next) {
+					return false
+				}
+			}
+			return true
+		}
+		visit(start)
+	}
+}
+
+// HasCycle reports whether the graph contains a directed cycle. It colors
+// nodes as it goes: a node still on the DFS path that is reached again
+// closes a cycle.
+func (g *Graph[T]) HasCycle() bool {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[T]int)
+	var visit func(n T) bool
+	visit = func(n T) bool {
+		state[n] = onPath
+		for _, next := range g.edges[n] {
+			switch state[next] {
+			case onPath:
+				return true
+			case unvisited:
+				if visit(next) {
+					return true
+				}
+			}
+		}
+		state[n] = done
+		return false
+	}
+	for n := range g.edges {
+		if state[n] == unvisited && visit(n) {
+			return true
+		}
+	}
+	return false
+}
+
+func DemoGraph(w io.Writer) error {
+	var deps Graph[string]
+	for _, e := range [][2]string{
+		{"app", "http"}, {"app", "db"},
+		{"http", "net"}, {"http", "tls"},
+		{"db", "net"}, {"tls", "crypto"},
+	} {
+		deps.AddEdge(e[0], e[1])
+	}
+	deps.AddNode("tools") // nothing depends on it and it depends on nothing
+
+	fmt.Fprintln(w, "BFS from app:", slices.Collect(deps.BFS("app")))
+	fmt.Fprintln(w, "DFS from app:", slices.Collect(deps.DFS("app")))
+	fmt.Fprintln(w, "BFS from tools:", slices.Collect(deps.BFS("tools")))
+	fmt.Fprintln(w, "Reachable from app includes tools:", slices.Contains(slices.Collect(deps.BFS("app")), "tools"))
+	fmt.Fprintln(w, "Dependency graph has a cycle:", deps.HasCycle())
+
+	deps.AddEdge("crypto", "app")
+	fmt.Fprintln(w, "After adding crypto -> app, has a cycle:", deps.HasCycle())
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 93. crypto/tls: An in-memory CA for tests
+//
+// Tests that need TLS should not depend on certificate files or the system
+// trust store. newInMemoryTLS mints a throwaway CA and a server certificate
+// signed by it, and returns matching configs: the server presents the leaf,
+// and the client trusts only the CA. Combined with tlsHandshake over
+// net.Pipe, a full handshake needs no network at all.
+
+// newInMemoryTLS returns server and client configs for host, backed by a
+// freshly generated CA that only this client trusts.
+func newInMemoryTLS(host string) (serverConf, clientConf *tls.Config, err error) {
+	now := time.Now()
+	ca, caKey, err := issueCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "In-Memory Test CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating CA: %w", err)
+	}
+	leaf, leafKey, err := issueCert(&x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating server certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	serverConf = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{leaf.Raw}, PrivateKey: leafKey, Leaf: leaf}},
+	}
+	clientConf = &tls.Config{RootCAs: pool, ServerName: host}
+	return serverConf, clientConf, nil
+}
+
+func DemoTLSInMemory(w io.Writer) error {
+	serverConf, clientConf, err := newInMemoryTLS("demo.test")
+	if err != nil {
+		return err
+	}
+	client, server, err := tlsHandshake(serverConf, clientConf)
+	if err != nil {
+		return fmt.Errorf("TLS handshake: %w", err)
+	}
+	defer closePipe(client, server)
+
+	// net.Pipe is unbuffered, so the write needs a concurrent reader.
+	go client.Write([]byte{'!'})
+	buf := make([]byte, 1)
+	if _, err := io.ReadFull(server, buf); err != nil {
+		return fmt.Errorf("reading from client: %w", err)
+	}
+	state := client.ConnectionState()
+	fmt.Fprintf(w, "In-memory TLS: %s handshake with %s verified by %q, server received %q\n",
+		tls.VersionName(state.Version), state.PeerCertificates[0].Subject.CommonName,
+		state.VerifiedChains[0][1].Subject.CommonName, buf)
+
+	// A client that trusts a different CA must reject the server.
+	_, otherClient, err := newInMemoryTLS("demo.test")
+	if err != nil {
+		return err
+	}
+	_, _, err = tlsHandshake(serverConf, otherClient)
+	var verr *tls.CertificateVerificationError
+	if !errors.As(err, &verr) {
+		return fmt.Errorf("client trusting another CA: want a verification error, got %v", err)
+	}
+	fmt.Fprintln(w, "Client trusting another CA rejected the server:", verr.Err)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 94. strings: Multi-pattern replacement with Replacer
+//
+// A strings.Replacer applies every old/new pair in a single pass over the
+// input, so replacement text is never rescanned. Chained ReplaceAll calls
+// make one pass (and one new string) per pair, and a later pair can rewrite
+// the output of an earlier one. When patterns overlap at the same position,
+// the Replacer tries them in argument order and the first match wins.
+
+// wordPairs are four substitutions that cannot interact, so wordReplacer and
+// replaceWordsChained agree on any input; BenchmarkReplacer compares their
+// cost.
+var (
+	wordPairs    = [][2]string{{"quick", "slow"}, {"brown", "grey"}, {"fox", "cat"}, {"dog", "mouse"}}
+	wordReplacer = strings.NewReplacer("quick", "slow", "brown", "grey", "fox", "cat", "dog", "mouse")
+)
+
+// replaceWordsChained applies wordPairs with one ReplaceAll pass per pair.
+func replaceWordsChained(s string) string {
+	for _, pair := range wordPairs {
+		s = strings.ReplaceAll(s, pair[0], pair[1])
+	}
+	return s
+}
+
+func DemoReplacer(w io.Writer) error {
+	escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	chained := func(s string) string {
+		s = strings.ReplaceAll(s, "<", "&lt;")
+		s = strings.ReplaceAll(s, ">", "&gt;")
+		return strings.ReplaceAll(s, "&", "&amp;")
+	}
+	input := "<b>Fish & Chips</b>"
+	if got, want := escaper.Replace(input), "&lt;b&gt;Fish &amp; Chips&lt;/b&gt;"; got != want {
+		return fmt.Errorf("Replacer escaped %q as %q, want %q", input, got, want)
+	}
+	fmt.Fprintln(w, "Replacer:          ", escaper.Replace(input))
+	fmt.Fprintln(w, "Chained ReplaceAll:", chained(input), "(& rewritten inside &lt;)")
+
+	for _, tt := range []struct {
+		pairs []string
+		want  string
+	}{
+		{[]string{"a", "1", "aa", "2"}, "111"},
+		{[]string{"aa", "2", "a", "1"}, "21"},
+	} {
+		got := strings.NewReplacer(tt.pairs...).Replace("aaa")
+		if got != tt.want {
+			return fmt.Errorf("Replacer%q on \"aaa\" = %q, want %q", tt.pairs, got, tt.want)
+		}
+		fmt.Fprintf(w, "Overlap %q on \"aaa\": %s\n", tt.pairs, got)
+	}
+
+	sentence := "the quick brown fox jumps over the lazy dog"
+	replaced := wordReplacer.Replace(sentence)
+	if chainedOut := replaceWordsChained(sentence); replaced != chainedOut {
+		return fmt.Errorf("Replacer gave %q but chained ReplaceAll gave %q", replaced, chainedOut)
+	}
+	fmt.Fprintln(w, "Four words replaced in one pass:", replaced)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// 95. crypto/sha3 and Pool[T]: Reusing hashers
+//
+// A SHA-3 hasher carries a 200-byte sponge state. When it stays local the
+// compiler can keep it on the stack, but once it escapes, as with a hash.Hash
+// returned by NewSHA3Hasher, every message costs a heap allocation. Pool[T]
+// with Reset as the reset function hands out hashers that are always in
+// their initial state, safe to share across goroutines one Get at a time.
+
+var sha3Pool = NewPool(sha3.New256, (*sha3.SHA3).Reset)
+
+// pooledSHA3Sum256 hashes data with a hasher borrowed from sha3Pool.
+func pooledSHA3Sum256(data []byte) [32]byte {
+	h := sha3Pool.Get()
+	defer sha3Pool.Put(h)
+	h.Write(data)
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+func DemoHasherPool(w io.Writer) error {
+	// A hasher put back mid-message must come out of the pool clean.
+	dirty := sha3Pool.Get()
+	dirty.Write([]byte("half of a message"))
+	sha3Pool.Put(dirty)
+	msg := []byte("pooled hashers")
+	pooledSum := pooledSHA3Sum256(msg)
+	if want := sha3.Sum256(msg); pooledSum != want {
+		return fmt.Errorf("pooled hasher was not reset: got %x, want %x", pooledSum, want)
+	}
+	fmt.Fprintf(w, "Pooled SHA3-256 after a dirty Put: %x\n", pooledSum)
+
+	const workers, perWorker = 16, 200
+	var mismatches atomic.Int32
+	fns := make([]func(), workers)
+	for i := range fns {
+		fns[i] = func() {
+			for j := range perWorker {
+				data := fmt.Appendf(nil, "worker %d message %d", i, j)
+				if pooledSHA3Sum256(data) != sha3.Sum256(data) {
+					mismatches.Add(1)
+				}
+			}
+		}
+	}
+	goAll(fns...)
+	if n := mismatches.Load(); n > 0 {
+		return fmt.Errorf("%d of %d concurrent pooled hashes were wrong", n, workers*perWorker)
+	}
+	fmt.Fprintf(w, "%d goroutines hashed %d messages with pooled hashers, all correct\n", workers, workers*perWorker)
+
+	// A hasher from NewSHA3Hasher is allocated on every call;
+	// BenchmarkSHA3Pooled measures what the pool saves.
+	h, err := NewSHA3Hasher(256)
+	if err != nil {
+		return err
+	}
+	h.Write(msg)
+	if fresh := h.Sum(nil); !bytes.Equal(fresh, pooledSum[:]) {
+		return fmt.Errorf("fresh hasher gave %x, pooled gave %x", fresh, pooledSum)
+	}
+	fmt.Fprintln(w, "A fresh hasher from NewSHA3Hasher agrees with the pooled one")
+	return nil
+}
+
+// notImplemented returns a placeholder demo for a feature without one yet.
+func notImplemented(title string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, title+": Not implemented")
+		return err
+	}
+}
+
+// demos lists every demo in the order RunAll runs them.
+var demos = []struct {
+	name string
+	run  func(io.Writer) error
+}{
+	{"demoGenericTypeAlias", demoGenericTypeAlias},
+	{"CGO Improvements", notImplemented("CGO Improvements Demo")},
+	{"DemoFinalizers", DemoFinalizers},
+	{"DemoCryptoPackages", DemoCryptoPackages},
+	{"DemoDirectoryLimitedFS", DemoDirectoryLimitedFS},
+	{"DemoBytesAndStringsIterators", DemoBytesAndStringsIterators},
+	{"DemoEncodingAppend", DemoEncodingAppend},
+	{"DemoNetipEncoding", DemoNetipEncoding},
+	{"DemoRegexpEncoding", DemoRegexpEncoding},
+	{"DemoRuntimeGOROOT", DemoRuntimeGOROOT},
+	{"DemoTextTemplate", DemoTextTemplate},
+	{"DemoMathBigEncoding", DemoMathBigEncoding},
+	{"DemoMathRand", DemoMathRand},
+	{"DemoSyncMap", DemoSyncMap},
+	{"DemoSlog", DemoSlog},
+	{"Text Template Range", notImplemented("Text Template Range Demo")},
+	{"DemoTimeEncoding", DemoTimeEncoding},
+	{"DemoSynctest", DemoSynctest},
+	{"DemoGoTypesIterators", DemoGoTypesIterators},
+	{"DemoMaphashComparable", DemoMaphashComparable},
+	{"DemoHTTPClientRetry", DemoHTTPClientRetry},
+	{"DemoDocComment", DemoDocComment},
+	{"DemoHashSlice", DemoHashSlice},
+	{"DemoKeyingMaterial", DemoKeyingMaterial},
+	{"DemoBits", DemoBits},
+	{"DemoPathValue", DemoPathValue},
+	{"DemoTimeLayouts", DemoTimeLayouts},
+	{"DemoEventBus", DemoEventBus},
+	{"DemoMLKEM", DemoMLKEM},
+	{"DemoFSStat", DemoFSStat},
+	{"DemoLazyInit", DemoLazyInit},
+	{"DemoJSONNumber", DemoJSONNumber},
+	{"DemoRetry", DemoRetry},
+	{"DemoTypeChecker", DemoTypeChecker},
+	{"DemoEncryptFile", DemoEncryptFile},
+	{"DemoSlicesMinMax", DemoSlicesMinMax},
+	{"DemoNetipMapKey", DemoNetipMapKey},
+	{"DemoSplitSeq", DemoSplitSeq},
+	{"DemoTLSPolicy", DemoTLSPolicy},
+	{"DemoOptions", DemoOptions},
+	{"DemoMemoryLimit", DemoMemoryLimit},
+	{"DemoCustomJSONTime", DemoCustomJSONTime},
+	{"DemoScanner", DemoScanner},
+	{"DemoWaitGroupGo", DemoWaitGroupGo},
+	{"DemoTreeHash", DemoTreeHash},
+	{"DemoURLBuild", DemoURLBuild},
+	{"DemoReflectClear", DemoReflectClear},
+	{"DemoWorkerPool", DemoWorkerPool},
+	{"DemoTimerGC", DemoTimerGC},
+	{"DemoAPIToken", DemoAPIToken},
+	{"DemoJSONIndent", DemoJSONIndent},
+	{"DemoCIDRMigration", DemoCIDRMigration},
+	{"DemoMemoize", DemoMemoize},
+	{"DemoSlogLevelVar", DemoSlogLevelVar},
+	{"DemoBytesReader", DemoBytesReader},
+	{"DemoImporter", DemoImporter},
+	{"DemoSet", DemoSet},
+	{"DemoCertChain", DemoCertChain},
+	{"DemoTemplateBreakContinue", DemoTemplateBreakContinue},
+	{"DemoMultiWriter", DemoMultiWriter},
+	{"DemoHashSeedStability", DemoHashSeedStability},
+	{"DemoRingBuffer", DemoRingBuffer},
+	{"DemoBinaryJSON", DemoBinaryJSON},
+	{"DemoSlicesEqual", DemoSlicesEqual},
+	{"DemoStreamUpload", DemoStreamUpload},
+	{"DemoStructLayout", DemoStructLayout},
+	{"DemoRandReader", DemoRandReader},
+	{"DemoZeroTime", DemoZeroTime},
+	{"DemoPipeline", DemoPipeline},
+	{"DemoSlogHandlers", DemoSlogHandlers},
+	{"DemoCleanupVsFinalizer", DemoCleanupVsFinalizer},
+	{"DemoJSONToFile", DemoJSONToFile},
+	{"DemoSafeReadFile", DemoSafeReadFile},
+	{"DemoBigBase", DemoBigBase},
+	{"DemoDebounce", DemoDebounce},
+	{"DemoHeaderParse", DemoHeaderParse},
+	{"DemoHashFamily", DemoHashFamily},
+	{"DemoCollectLimited", DemoCollectLimited},
+	{"DemoZoneConvert", DemoZoneConvert},
+	{"DemoResourceHandle", DemoResourceHandle},
+	{"DemoTypedPool", DemoTypedPool},
+	{"DemoJSONTransform", DemoJSONTransform},
+	{"DemoRootErrors", DemoRootErrors},
+	{"DemoTrie", DemoTrie},
+	{"DemoConstantTimeSelect", DemoConstantTimeSelect},
+	{"DemoStackDump", DemoStackDump},
+	{"DemoFileServerFS", DemoFileServerFS},
+	{"DemoMultiKeySort", DemoMultiKeySort},
+	{"DemoFuture", DemoFuture},
+	{"DemoValidatingUnmarshal", DemoValidatingUnmarshal},
+	{"DemoMathBits", DemoMathBits},
+	{"DemoGraph", DemoGraph},
+	{"DemoTLSInMemory", DemoTLSInMemory},
+	{"DemoReplacer", DemoReplacer},
+	{"DemoHasherPool", DemoHasherPool},
+}
+
+// RunAll runs every demo, writing their output to w. A failing demo does not
+// stop the others; RunAll returns the failures joined with errors.Join, each
+// prefixed with the demo's name, or nil if all succeeded.
+func RunAll(w io.Writer) error {
+	fmt.Fprintln(w, "=== Go 1.24 Demo ===")
+	var errs []error
+	for _, demo := range demos {
+		if err := demo.run(w); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", demo.name, err))
+		}
+	}
+	fmt.Fprintln(w, "=== Go 1.24 Demo End ===")
+	return errors.Join(errs...)
+}
+
+func main() {
+	// Tee the output into a buffer as well, to report how much was written.
+	var capture bytes.Buffer
+	err := RunAll(io.MultiWriter(os.Stdout, &capture))
+	fmt.Printf("Captured %d bytes of demo output\n", capture.Len())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Demo failures:")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
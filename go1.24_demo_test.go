@@ -0,0 +1,1002 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/mlkem"
+	"crypto/sha256"
+	"crypto/sha3"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+// pbkdf2SHA256Vector is the PBKDF2-HMAC-SHA256 test vector from RFC 7914,
+// section 11, for password "passwd", salt "salt", 1 iteration, and 64 bytes.
+const pbkdf2SHA256Vector = "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+	"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+
+func TestDeriveKey(t *testing.T) {
+	key, err := DeriveKey("passwd", []byte("salt"), 1, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(key); got != pbkdf2SHA256Vector {
+		t.Errorf("DeriveKey = %s, want %s", got, pbkdf2SHA256Vector)
+	}
+
+	for _, tt := range []struct {
+		name               string
+		iterations, keyLen int
+	}{
+		{"zero iterations", 0, 32},
+		{"negative iterations", -1, 32},
+		{"zero key length", 1, 0},
+		{"negative key length", 1, -1},
+	} {
+		if _, err := DeriveKey("passwd", []byte("salt"), tt.iterations, tt.keyLen); err == nil {
+			t.Errorf("%s: DeriveKey succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestNewSHA3HasherStreaming(t *testing.T) {
+	data := make([]byte, 4<<20+17) // not a multiple of the chunk size
+	randv2.NewChaCha8([32]byte{}).Read(data)
+
+	for _, bits := range []int{224, 256, 384, 512} {
+		h, err := NewSHA3Hasher(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.CopyBuffer(h, bytes.NewReader(data), make([]byte, 32<<10)); err != nil {
+			t.Fatal(err)
+		}
+		want, err := SHA3Digest(data, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("SHA3-%d streamed = %x, one-shot = %x", bits, got, want)
+		}
+	}
+	if _, err := NewSHA3Hasher(100); err == nil {
+		t.Error("NewSHA3Hasher(100) succeeded, want an error")
+	}
+}
+
+func TestCountNonEmptyLines(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"one", 1},
+		{"one\n", 1},
+		{"one\ntwo", 2},
+		{"one\ntwo\n", 2},
+		{"one\n\ntwo\n\n", 2},
+		{"\n\n", 0},
+		{" \t\n  \n", 0},
+		{"crlf\r\n\r\n", 1},
+	} {
+		if got := CountNonEmptyLines(tt.in); got != tt.want {
+			t.Errorf("CountNonEmptyLines(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMapSlice(t *testing.T) {
+	got := MapSlice(MySlice[int]{1, 2, 3}, func(n int) string { return "#" + strconv.Itoa(n) })
+	if want := (MySlice[string]{"#1", "#2", "#3"}); !slices.Equal(got, want) {
+		t.Errorf("MapSlice = %q, want %q", got, want)
+	}
+	for _, in := range []MySlice[int]{nil, {}} {
+		if got := MapSlice(in, strconv.Itoa); got == nil || len(got) != 0 {
+			t.Errorf("MapSlice(%#v) = %#v, want an empty non-nil slice", in, got)
+		}
+	}
+}
+
+func TestFilterAndReduceSlice(t *testing.T) {
+	numbers := MySlice[int]{1, 2, 3, 4, 5, 6}
+	evens := FilterSlice(numbers, func(n int) bool { return n%2 == 0 })
+	if want := (MySlice[int]{2, 4, 6}); !slices.Equal(evens, want) {
+		t.Errorf("FilterSlice evens = %v, want %v", evens, want)
+	}
+	if sum := ReduceSlice(evens, 0, func(acc, n int) int { return acc + n }); sum != 12 {
+		t.Errorf("ReduceSlice sum = %d, want 12", sum)
+	}
+
+	// The accumulator type differs from the element type.
+	joined := ReduceSlice(numbers, "", func(acc string, n int) string { return acc + strconv.Itoa(n) })
+	if joined != "123456" {
+		t.Errorf("ReduceSlice into a string = %q, want %q", joined, "123456")
+	}
+	if got := ReduceSlice(MySlice[int](nil), 7, func(acc, n int) int { return acc + n }); got != 7 {
+		t.Errorf("ReduceSlice of nil = %d, want the initial value 7", got)
+	}
+	if got := FilterSlice(MySlice[int](nil), func(int) bool { return true }); len(got) != 0 {
+		t.Errorf("FilterSlice of nil = %v, want empty", got)
+	}
+}
+
+func TestAllSlice(t *testing.T) {
+	numbers := MySlice[int]{10, 20, 30}
+	var indexes []int
+	var values MySlice[int]
+	for i, v := range AllSlice(numbers) {
+		indexes = append(indexes, i)
+		values = append(values, v)
+	}
+	if !slices.Equal(indexes, []int{0, 1, 2}) || !slices.Equal(values, numbers) {
+		t.Errorf("AllSlice yielded indexes %v and values %v, want [0 1 2] and %v", indexes, values, numbers)
+	}
+
+	// Breaking out of the loop stops the iterator.
+	n := 0
+	for range AllSlice(numbers) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("AllSlice kept yielding after break: %d iterations", n)
+	}
+}
+
+func TestHolderCleanups(t *testing.T) {
+	msgs, err := holderCleanups(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cleanups for the same object may run in any order.
+	want := []string{"cleanup 1 released value 42", "cleanup 2 released value 42"}
+	if got := slices.Sorted(slices.Values(msgs)); !slices.Equal(got, want) {
+		t.Errorf("cleanups sent %q, want %q", got, want)
+	}
+}
+
+// waitForRelease runs the GC until released yields a descriptor and returns
+// it, or reports false if none arrives before it gives up.
+func waitForRelease(released <-chan int) (int, bool) {
+	for range 100 {
+		runtime.GC()
+		select {
+		case fd := <-released:
+			return fd, true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return 0, false
+}
+
+// settleCleanups runs a few GC cycles to give any stray cleanup the chance
+// to run.
+func settleCleanups() {
+	for range 3 {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestResourceHandleReleasedOnce(t *testing.T) {
+	t.Parallel()
+	released := make(chan int, 4)
+	release := func(fd int) { released <- fd }
+
+	h := AcquireResource(1, release)
+	h.Close()
+	h.Close()
+	h = nil
+	settleCleanups()
+	if n := len(released); n != 1 {
+		t.Fatalf("closed handle released %d times, want 1", n)
+	}
+	if fd := <-released; fd != 1 {
+		t.Errorf("Close released fd %d, want 1", fd)
+	}
+
+	AcquireResource(2, release)
+	fd, ok := waitForRelease(released)
+	if !ok {
+		t.Fatal("timed out waiting for the cleanup of a dropped handle")
+	}
+	if fd != 2 {
+		t.Errorf("cleanup released fd %d, want 2", fd)
+	}
+	settleCleanups()
+	if n := len(released); n != 0 {
+		t.Errorf("dropped handle released %d more times, want 0", n)
+	}
+}
+
+func TestAppendAddrBinary(t *testing.T) {
+	for _, tt := range []struct {
+		addr string
+		len  int
+	}{
+		{"192.0.2.1", 4},
+		{"2001:db8::1", 16},
+	} {
+		a := netip.MustParseAddr(tt.addr)
+		want, err := a.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := AppendAddrBinary(a, nil)
+		if !bytes.Equal(got, want) || len(got) != tt.len {
+			t.Errorf("AppendAddrBinary(%s) = %x, want %x (%d bytes)", tt.addr, got, want, tt.len)
+		}
+		// Appending keeps the existing contents of dst.
+		if got := AppendAddrBinary(a, []byte{0xff}); !bytes.Equal(got, append([]byte{0xff}, want...)) {
+			t.Errorf("AppendAddrBinary(%s, [ff]) = %x", tt.addr, got)
+		}
+	}
+}
+
+func TestAppendPrefixAndAddrPortText(t *testing.T) {
+	prefix := netip.MustParsePrefix("192.0.2.0/24")
+	text := AppendPrefixText(prefix, []byte("net="))
+	if string(text) != "net=192.0.2.0/24" {
+		t.Errorf("AppendPrefixText = %q, want %q", text, "net=192.0.2.0/24")
+	}
+	if back, err := netip.ParsePrefix(strings.TrimPrefix(string(text), "net=")); err != nil || back != prefix {
+		t.Errorf("ParsePrefix(%q) = %v, %v; want %v", text, back, err, prefix)
+	}
+
+	addrPort := netip.MustParseAddrPort("[2001:db8::1]:443")
+	text = AppendAddrPortText(addrPort, nil)
+	if string(text) != "[2001:db8::1]:443" {
+		t.Errorf("AppendAddrPortText = %q, want %q", text, "[2001:db8::1]:443")
+	}
+	if back, err := netip.ParseAddrPort(string(text)); err != nil || back != addrPort {
+		t.Errorf("ParseAddrPort(%q) = %v, %v; want %v", text, back, err, addrPort)
+	}
+
+	if got := AppendPrefixText(netip.Prefix{}, nil); string(got) != "invalid Prefix" {
+		t.Errorf("AppendPrefixText(zero) = %q, want %q", got, "invalid Prefix")
+	}
+	if got := AppendAddrPortText(netip.AddrPort{}, nil); string(got) != "invalid AddrPort" {
+		t.Errorf("AppendAddrPortText(zero) = %q, want %q", got, "invalid AddrPort")
+	}
+}
+
+func TestDemoStructBinaryRoundTrip(t *testing.T) {
+	for _, ds := range []demoStruct{{0}, {123}, {-1}, {1 << 40}} {
+		bin, err := ds.AppendBinary([]byte("hdr:"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bin) != len("hdr:")+8 || string(bin[:4]) != "hdr:" {
+			t.Errorf("AppendBinary(%v) = %x, want the prefix followed by 8 bytes", ds, bin)
+		}
+		var back demoStruct
+		if err := back.UnmarshalBinary(bin[len("hdr:"):]); err != nil {
+			t.Fatal(err)
+		}
+		if back != ds {
+			t.Errorf("round trip of %v gave %v", ds, back)
+		}
+	}
+	var ds demoStruct
+	if err := ds.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Error("UnmarshalBinary of 3 bytes succeeded, want an error")
+	}
+}
+
+func TestAppendTextOf(t *testing.T) {
+	buf, err := AppendTextOf(demoStruct{Value: 123}, []byte("v="))
+	if err != nil || string(buf) != "v=demoStruct(123)" {
+		t.Errorf("AppendTextOf(demoStruct) = %q, %v; want %q", buf, err, "v=demoStruct(123)")
+	}
+	ts := time.Date(2025, time.February, 11, 12, 30, 0, 0, time.UTC)
+	buf, err = AppendTextOf(ts, buf[:0])
+	if want := "2025-02-11T12:30:00Z"; err != nil || string(buf) != want {
+		t.Errorf("AppendTextOf(time.Time) = %q, %v; want %q", buf, err, want)
+	}
+	// time.Time reports years outside [0,9999] as an error.
+	if _, err := AppendTextOf(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), nil); err == nil {
+		t.Error("AppendTextOf(year 10000) succeeded, want an error")
+	}
+}
+
+// failingText implements encoding.TextMarshaler with a failing MarshalText.
+type failingText struct{}
+
+func (failingText) MarshalText() ([]byte, error) { return nil, errors.New("cannot marshal") }
+
+func TestAppendTextOrMarshal(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"TextAppender", demoStruct{Value: 1}, "pre:demoStruct(1)"},
+		{"TextMarshaler preferred over Stringer", markedText("a"), "pre:text:a"},
+		{"Stringer only", markedString("b"), "pre:string:b"},
+	} {
+		got, err := AppendTextOrMarshal(tt.v, []byte("pre:"))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: AppendTextOrMarshal = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	for _, v := range []any{42, failingText{}} {
+		got, err := AppendTextOrMarshal(v, []byte("pre:"))
+		if err == nil {
+			t.Errorf("AppendTextOrMarshal(%T) succeeded, want an error", v)
+		}
+		if string(got) != "pre:" {
+			t.Errorf("AppendTextOrMarshal(%T) changed dst to %q on error", v, got)
+		}
+	}
+}
+
+func TestRenderCountTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		n    int
+		want string
+	}{
+		{0, "Numbers: "},
+		{1, "Numbers: 0 "},
+		{5, "Numbers: 0 1 2 3 4 "},
+	} {
+		got, err := RenderCountTemplate(tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("RenderCountTemplate(%d) = %q, %v; want %q", tt.n, got, err, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	data := struct {
+		Version string
+		Changes map[string]int
+	}{Version: "1.24", Changes: map[string]int{"runtime": 3, "crypto": 5, "os": 2}}
+
+	got, err := RenderTemplate(`Go {{.Version}} touches {{join (sortedKeys .Changes) ", "}}`, data)
+	if want := "Go 1.24 touches crypto, os, runtime"; err != nil || got != want {
+		t.Errorf("RenderTemplate = %q, %v; want %q", got, err, want)
+	}
+
+	// Parse error: the action is never closed, so the lone brace is rejected.
+	if got, err := RenderTemplate(`Go {{.Version}`, data); err == nil || !strings.Contains(err.Error(), "bad character") {
+		t.Errorf("unclosed action: got %q, %v; want parse error", got, err)
+	}
+	// Execution error: the template parses but the field does not exist.
+	if got, err := RenderTemplate(`Go {{.Codename}}`, data); err == nil || !strings.Contains(err.Error(), "can't evaluate field Codename") {
+		t.Errorf("missing field: got %q, %v; want execution error", got, err)
+	}
+}
+
+func TestDemos(t *testing.T) {
+	for _, d := range demos {
+		t.Run(d.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := d.run(&out); err != nil {
+				t.Fatalf("%s failed: %v\noutput:\n%s", d.name, err, out.String())
+			}
+			if out.Len() == 0 {
+				t.Errorf("%s wrote no output", d.name)
+			}
+		})
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	var out bytes.Buffer
+	if err := RunAll(&out); err != nil {
+		t.Fatalf("RunAll: %v", err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "=== Go 1.24 Demo ===\n") {
+		t.Errorf("output does not start with the header: %.40q", got)
+	}
+	if !strings.HasSuffix(got, "=== Go 1.24 Demo End ===\n") {
+		t.Errorf("output does not end with the footer: %.40q", got[max(0, len(got)-40):])
+	}
+
+	// Swap in demos with known output to check that each one's output
+	// appears, in order, and that failures are reported by name.
+	saved := demos
+	defer func() { demos = saved }()
+	errBroken := errors.New("broken")
+	demos = demos[:0:0]
+	for _, name := range []string{"first", "second", "third"} {
+		demos = append(demos, struct {
+			name string
+			run  func(io.Writer) error
+		}{name, func(w io.Writer) error {
+			fmt.Fprintln(w, "output of", name)
+			if name == "second" {
+				return errBroken
+			}
+			return nil
+		}})
+	}
+	out.Reset()
+	err := RunAll(&out)
+	want := "=== Go 1.24 Demo ===\noutput of first\noutput of second\noutput of third\n=== Go 1.24 Demo End ===\n"
+	if out.String() != want {
+		t.Errorf("RunAll output = %q, want %q", out.String(), want)
+	}
+	if !errors.Is(err, errBroken) || !strings.Contains(err.Error(), "second: broken") {
+		t.Errorf("RunAll error = %v, want %q", err, "second: broken")
+	}
+}
+
+func TestRandomInts(t *testing.T) {
+	a, b := RandomInts(42, 1000), RandomInts(42, 1000)
+	if !slices.Equal(a, b) {
+		t.Fatal("RandomInts(42, 1000) differs between calls")
+	}
+	for i, v := range a {
+		if v < 0 || v >= 100 {
+			t.Fatalf("RandomInts(42, 1000)[%d] = %d, want [0, 100)", i, v)
+		}
+	}
+	if got, want := RandomInts(42, 5), []int{5, 87, 68, 50, 23}; !slices.Equal(got, want) {
+		t.Errorf("RandomInts(42, 5) = %v, want %v", got, want)
+	}
+	if slices.Equal(a, RandomInts(43, 1000)) {
+		t.Error("RandomInts gave the same slice for seeds 42 and 43")
+	}
+	if got := RandomInts(42, 0); len(got) != 0 {
+		t.Errorf("RandomInts(42, 0) = %v, want empty", got)
+	}
+}
+
+func TestShuffleDeterministic(t *testing.T) {
+	deck := []string{"A", "K", "Q", "J", "10", "9"}
+	for _, tt := range []struct {
+		seed uint64
+		want []string
+	}{
+		{7, []string{"Q", "K", "10", "J", "A", "9"}},
+		{8, []string{"9", "Q", "10", "J", "K", "A"}},
+	} {
+		got := slices.Clone(deck)
+		ShuffleDeterministic(got, tt.seed)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ShuffleDeterministic(seed %d) = %v, want %v", tt.seed, got, tt.want)
+		}
+	}
+
+	// Any seed must produce a permutation of the input.
+	nums := make([]int, 100)
+	for i := range nums {
+		nums[i] = i
+	}
+	ShuffleDeterministic(nums, 1)
+	slices.Sort(nums)
+	for i, v := range nums {
+		if v != i {
+			t.Fatalf("ShuffleDeterministic lost or duplicated elements: sorted[%d] = %d", i, v)
+		}
+	}
+}
+
+func TestConcurrentCounter(t *testing.T) {
+	const workers, increments = 50, 1000
+	counts := ConcurrentCounter(workers, increments)
+	if len(counts) != 8 {
+		t.Errorf("ConcurrentCounter used %d keys, want 8", len(counts))
+	}
+	total := 0
+	for key, n := range counts {
+		if n == 0 {
+			t.Errorf("counter %s was never incremented", key)
+		}
+		total += n
+	}
+	if total != workers*increments {
+		t.Errorf("ConcurrentCounter(%d, %d) counted %d increments, want %d", workers, increments, total, workers*increments)
+	}
+}
+
+func TestDeleteMatching(t *testing.T) {
+	var m sync.Map
+	for i := range 100 {
+		m.Store(i, i)
+	}
+	deleted := DeleteMatching(&m, func(_, value any) bool { return value.(int)%2 == 0 })
+	if deleted != 50 {
+		t.Errorf("DeleteMatching deleted %d entries, want 50", deleted)
+	}
+	var survivors []int
+	m.Range(func(key, _ any) bool {
+		survivors = append(survivors, key.(int))
+		return true
+	})
+	slices.Sort(survivors)
+	if len(survivors) != 50 {
+		t.Fatalf("%d entries survived, want 50: %v", len(survivors), survivors)
+	}
+	for i, k := range survivors {
+		if k != 2*i+1 {
+			t.Fatalf("survivors[%d] = %d, want %d", i, k, 2*i+1)
+		}
+	}
+
+	if n := DeleteMatching(&m, func(_, _ any) bool { return false }); n != 0 {
+		t.Errorf("DeleteMatching with a false predicate deleted %d entries", n)
+	}
+}
+
+func TestMLKEMFixedSeed(t *testing.T) {
+	dk, err := mlkem.NewDecapsulationKey768(mlkemDemoSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek := dk.EncapsulationKey()
+	digest := sha3.Sum256(ek.Bytes())
+	if got := hex.EncodeToString(digest[:]); got != mlkemDemoKeyDigest {
+		t.Errorf("encapsulation key digest = %s, want %s", got, mlkemDemoKeyDigest)
+	}
+
+	sharedKey, ciphertext := ek.Encapsulate()
+	recovered, err := dk.Decapsulate(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sharedKey, recovered) {
+		t.Errorf("Decapsulate = %x, want %x", recovered, sharedKey)
+	}
+}
+
+func TestLazyLoadConcurrent(t *testing.T) {
+	var cache sync.Map
+	var calls [3]atomic.Int32
+	newValue := func(key int) int {
+		calls[key].Add(1)
+		return key * 10
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		for key := range len(calls) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := lazyLoad(&cache, key, newValue); got != key*10 {
+					t.Errorf("lazyLoad(%d) = %d, want %d", key, got, key*10)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for key := range calls {
+		if n := calls[key].Load(); n != 1 {
+			t.Errorf("constructor ran %d times for key %d, want 1", n, key)
+		}
+	}
+}
+
+func TestMemoizeOncePerKey(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	length := Memoize(func(s string) int {
+		mu.Lock()
+		calls[s]++
+		mu.Unlock()
+		return len(s)
+	})
+
+	keys := []string{"", "a", "gopher"}
+	var wg sync.WaitGroup
+	for range 50 {
+		for _, key := range keys {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := length(key); got != len(key) {
+					t.Errorf("length(%q) = %d, want %d", key, got, len(key))
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, key := range keys {
+		if calls[key] != 1 {
+			t.Errorf("f ran %d times for %q, want 1", calls[key], key)
+		}
+	}
+}
+
+// splitInputs generates s and sep from a small alphabet so that separators
+// actually occur in the input. sep may be empty, which splits into UTF-8
+// sequences in both functions.
+func splitInputs(args []reflect.Value, r *rand.Rand) {
+	const alphabet = "ab,é"
+	runes := []rune(alphabet)
+	gen := func(maxLen int) string {
+		var sb strings.Builder
+		for range r.Intn(maxLen + 1) {
+			sb.WriteRune(runes[r.Intn(len(runes))])
+		}
+		return sb.String()
+	}
+	args[0] = reflect.ValueOf(gen(12))
+	args[1] = reflect.ValueOf(gen(2))
+}
+
+func TestSplitSeqProperty(t *testing.T) {
+	property := func(s, sep string) bool {
+		return slices.Equal(slices.Collect(strings.SplitSeq(s, sep)), strings.Split(s, sep))
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000, Values: splitInputs}); err != nil {
+		t.Error(err)
+	}
+}
+
+// countingReader returns a reader of the n bytes 0x00, 0x01, ...
+func countingReader(n int) io.Reader {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return bytes.NewReader(b)
+}
+
+func TestNewUUID(t *testing.T) {
+	id, err := NewUUID(countingReader(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "00010203-0405-4607-8809-0a0b0c0d0e0f"; id != want {
+		t.Errorf("NewUUID(fixed reader) = %s, want %s", id, want)
+	}
+
+	id, err = NewUUID(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(id) != 36 || id[14] != '4' || !strings.ContainsRune("89ab", rune(id[19])) {
+		t.Errorf("NewUUID(nil) = %s, want a version 4, RFC 9562 variant UUID", id)
+	}
+
+	if _, err := NewUUID(countingReader(8)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("NewUUID(short reader) error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestNewTokenKeyFixedReader(t *testing.T) {
+	key, err := NewTokenKey(countingReader(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	const want = "dXNlci00MnwxODkzNDU2MDAw.Vc7ft57OP2A10B-xoUHrjG3Cn_ZwkC_00J4et5FD6uA"
+	if got := SignToken(key, "user-42", expires); got != want {
+		t.Errorf("SignToken = %s, want %s", got, want)
+	}
+
+	if _, err := NewTokenKey(countingReader(31)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("NewTokenKey(short reader) error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestCollectCanceled(t *testing.T) {
+	// Once the stages have exited, both the closed channel and the canceled
+	// context are ready and select picks either at random, so repeat.
+	for range 100 {
+		before := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		in := Transform(ctx, Generate(ctx, Primes()), func(p int) int { return p })
+		<-in
+		cancel()
+		if !goroutinesSettled(before) {
+			t.Fatal("pipeline goroutines did not exit after cancel")
+		}
+		if _, err := Collect(ctx, in); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Collect after cancel: error = %v, want context.Canceled", err)
+		}
+	}
+
+	got, err := Collect(context.Background(), Generate(context.Background(), slices.Values([]int{1, 2, 3})))
+	if err != nil || !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Collect = %v, %v; want [1 2 3], nil", got, err)
+	}
+}
+
+// hkdfSHA256Vector is the output keying material of RFC 5869, test case 1.
+const hkdfSHA256Vector = "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+func TestHKDFExpand(t *testing.T) {
+	// RFC 5869, test case 1.
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	okm, err := HKDFExpand(ikm, salt, info, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(okm); got != hkdfSHA256Vector {
+		t.Errorf("HKDFExpand = %s, want %s", got, hkdfSHA256Vector)
+	}
+
+	if okm, err := HKDFExpand(ikm, nil, nil, 255*sha256.Size); err != nil || len(okm) != 255*sha256.Size {
+		t.Errorf("HKDFExpand at the maximum length: got %d bytes, %v", len(okm), err)
+	}
+	for _, length := range []int{-1, 0, 255*sha256.Size + 1} {
+		if _, err := HKDFExpand(ikm, nil, nil, length); err == nil {
+			t.Errorf("HKDFExpand(length %d) succeeded, want an error", length)
+		}
+	}
+}
+
+// sha3EmptyVectors are the FIPS 202 digests of the empty string, keyed by
+// SHA3 digest size in bits.
+var sha3EmptyVectors = map[int]string{
+	224: "6b4e03423667dbb73b6e15454f0eb1abd4597f9a1b078e3f5b5a6bc7",
+	256: "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+	384: "0c63a75b845e4f7d01107d852e4c2485c51a50aaaa94fc61995e71bbee983a2ac3713831264adb47fb6bd1e058d5f004",
+	512: "a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a615b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26",
+}
+
+// The first 32 bytes of SHAKE128 and 64 bytes of SHAKE256 output for the
+// empty string, from FIPS 202.
+const (
+	shake128EmptyVector = "7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26"
+	shake256EmptyVector = "46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762f" +
+		"d75dc4ddd8c0f200cb05019d67b592f6fc821c49479ab48640292eacb3b7c4be"
+)
+
+func TestSHA3EmptyVectors(t *testing.T) {
+	for _, bits := range []int{224, 256, 384, 512} {
+		d, err := SHA3Digest(nil, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(d); got != sha3EmptyVectors[bits] {
+			t.Errorf("SHA3Digest(nil, %d) = %s, want %s", bits, got, sha3EmptyVectors[bits])
+		}
+	}
+	if _, err := SHA3Digest(nil, 128); err == nil {
+		t.Error("SHA3Digest(nil, 128) succeeded, want an error")
+	}
+}
+
+func TestSHAKEEmptyVectors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		shake256 bool
+		want     string
+	}{
+		{"SHAKE128", false, shake128EmptyVector},
+		{"SHAKE256", true, shake256EmptyVector},
+	} {
+		if got := hex.EncodeToString(SHAKE(nil, len(tt.want)/2, tt.shake256)); got != tt.want {
+			t.Errorf("%s of the empty string = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if out := SHAKE(nil, 0, true); len(out) != 0 {
+		t.Errorf("SHAKE(outputLen 0) = %x, want empty output", out)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("SHAKE(outputLen -1) did not panic")
+		}
+	}()
+	SHAKE(nil, -1, true)
+}
+
+func TestFinalizerAndCleanups(t *testing.T) {
+	order, err := finalizerAndCleanups(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 4 || order[0] != "finalizer" {
+		t.Fatalf("events = %v, want the finalizer followed by 3 cleanups", order)
+	}
+	cleanups := slices.Sorted(slices.Values(order[1:]))
+	if want := []string{"cleanup 1", "cleanup 2", "cleanup 3"}; !slices.Equal(cleanups, want) {
+		t.Errorf("cleanups = %v, want each of %v once", cleanups, want)
+	}
+}
+
+func TestRootRejectsEscapes(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(dir, "outside.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sandbox := filepath.Join(dir, "sandbox")
+	if err := os.Mkdir(sandbox, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(sandbox, "link")); err != nil {
+		t.Fatal(err)
+	}
+	root, err := os.OpenRoot(sandbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	for _, tt := range []struct {
+		name string
+		op   func() error
+	}{
+		{"Open ..", func() error { _, err := root.Open("../outside.txt"); return err }},
+		{"Open absolute", func() error { _, err := root.Open(outside); return err }},
+		{"Open symlink", func() error { _, err := root.Open("link"); return err }},
+		{"Create ..", func() error { _, err := root.Create("../outside.txt"); return err }},
+		{"Stat ..", func() error { _, err := root.Stat("../outside.txt"); return err }},
+		{"Mkdir ..", func() error { return root.Mkdir("../escaped", 0o755) }},
+		{"Chmod ..", func() error { return rootChmod(root, "../outside.txt", 0o600) }},
+	} {
+		if err := tt.op(); err == nil {
+			t.Errorf("%s: succeeded, want an error", tt.name)
+		}
+	}
+
+	if fi, err := os.Stat(outside); err != nil || fi.Mode().Perm() != 0o644 {
+		t.Errorf("outside.txt changed: %v, %v", fi, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Mkdir escaped the root: %v", err)
+	}
+}
+
+func TestSafeReadFile(t *testing.T) {
+	dir := t.TempDir()
+	public := filepath.Join(dir, "public")
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.MkdirAll(filepath.Join(public, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(secret, []byte("do not serve"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(public, "docs", "index.txt"), []byte("welcome"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("docs", "index.txt"), filepath.Join(public, "home.txt")); err != nil {
+		t.Skip("symlinks unsupported:", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(public, "leak.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..", "..", "secret.txt"), filepath.Join(public, "docs", "up.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"docs/index.txt", "home.txt"} {
+		if data, err := SafeReadFile(public, p); err != nil || string(data) != "welcome" {
+			t.Errorf("SafeReadFile(%q) = %q, %v; want %q", p, data, err, "welcome")
+		}
+	}
+	for _, p := range []string{"../secret.txt", "docs/../../secret.txt", secret, "leak.txt", "docs/up.txt"} {
+		if data, err := SafeReadFile(public, p); !errors.Is(err, ErrPathTraversal) {
+			t.Errorf("SafeReadFile(%q) = %q, %v; want ErrPathTraversal", p, data, err)
+		}
+	}
+	_, err := SafeReadFile(public, "missing.txt")
+	if !errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrPathTraversal) {
+		t.Errorf("SafeReadFile(missing) error = %v, want fs.ErrNotExist only", err)
+	}
+}
+
+func TestPoolResetsOnPut(t *testing.T) {
+	buffers := NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+	buf := buffers.Get()
+	buf.WriteString("left over")
+	buffers.Put(buf)
+	// Put resets before pooling, so even the same buffer comes back empty.
+	if buf.Len() != 0 {
+		t.Errorf("buffer after Put holds %q, want it reset", buf.String())
+	}
+	if got := buffers.Get(); got.Len() != 0 {
+		t.Errorf("Get returned a buffer holding %q", got.String())
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	n := big.NewInt(1 << 62)
+	render := func(buf *bytes.Buffer) {
+		buf.Write(n.Append(buf.AvailableBuffer(), 16))
+		buf.WriteString(" rendered")
+	}
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			render(new(bytes.Buffer))
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		buffers := NewPool(func() *bytes.Buffer { return new(bytes.Buffer) }, (*bytes.Buffer).Reset)
+		b.ReportAllocs()
+		for b.Loop() {
+			buf := buffers.Get()
+			render(buf)
+			buffers.Put(buf)
+		}
+	})
+}
+
+func BenchmarkReplacer(b *testing.B) {
+	large := strings.Repeat("the quick brown fox jumps over the lazy dog ", 10_000)
+	if wordReplacer.Replace(large) != replaceWordsChained(large) {
+		b.Fatal("Replacer and chained ReplaceAll disagree")
+	}
+	b.Run("replacer", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			wordReplacer.Replace(large)
+		}
+	})
+	b.Run("chained", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			replaceWordsChained(large)
+		}
+	})
+}
+
+func TestPooledSHA3Sum256(t *testing.T) {
+	dirty := sha3Pool.Get()
+	dirty.Write([]byte("half of a message"))
+	sha3Pool.Put(dirty)
+	for _, msg := range []string{"", "pooled hashers", strings.Repeat("x", 1000)} {
+		if got, want := pooledSHA3Sum256([]byte(msg)), sha3.Sum256([]byte(msg)); got != want {
+			t.Errorf("pooledSHA3Sum256(%.20q) = %x, want %x", msg, got, want)
+		}
+	}
+}
+
+func BenchmarkSHA3Pooled(b *testing.B) {
+	msg := []byte("pooled hashers")
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			h, _ := NewSHA3Hasher(256)
+			h.Write(msg)
+			var sum [32]byte
+			h.Sum(sum[:0])
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			pooledSHA3Sum256(msg)
+		}
+	})
+}
+
+func BenchmarkParseCIDR(b *testing.B) {
+	const cidr = "10.1.2.3/16"
+	b.Run("net", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			net.ParseCIDR(cidr)
+		}
+	})
+	b.Run("netip", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			netip.ParsePrefix(cidr)
+		}
+	})
+}
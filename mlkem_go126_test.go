@@ -0,0 +1,42 @@
+//go:build go1.26
+
+package main
+
+import (
+	"bytes"
+	"crypto/mlkem"
+	"crypto/mlkem/mlkemtest"
+	"encoding/hex"
+	"testing"
+)
+
+// TestMLKEMKnownAnswer pins the shared secret as well as the key. The public
+// Encapsulate draws fresh randomness, so this uses the derandomized
+// encapsulation from crypto/mlkem/mlkemtest, added in Go 1.26, with the
+// encapsulation randomness fixed to the bytes 0x40 through 0x5f.
+func TestMLKEMKnownAnswer(t *testing.T) {
+	const wantSharedKey = "9cddd089ffe70e3996e76f7c8d06746df34d07e8657bc0fcf2bb0e1c3084aea1"
+
+	dk, err := mlkem.NewDecapsulationKey768(mlkemDemoSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := make([]byte, 32)
+	for i := range random {
+		random[i] = byte(0x40 + i)
+	}
+	sharedKey, ciphertext, err := mlkemtest.Encapsulate768(dk.EncapsulationKey(), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(sharedKey); got != wantSharedKey {
+		t.Errorf("shared key = %s, want %s", got, wantSharedKey)
+	}
+	recovered, err := dk.Decapsulate(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, sharedKey) {
+		t.Errorf("Decapsulate = %x, want %x", recovered, sharedKey)
+	}
+}
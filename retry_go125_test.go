@@ -0,0 +1,39 @@
+//go:build go1.25
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// TestRetryBackoffCapped runs Retry in a synctest bubble, where its waits take
+// no real time, and checks that the gap between attempts doubles from
+// retryBaseDelay but never exceeds retryMaxDelay plus the same in jitter.
+func TestRetryBackoffCapped(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		const attempts = 16
+		errFail := errors.New("fail")
+		var calls []time.Time
+		_, err := Retry(context.Background(), attempts, func() (int, error) {
+			calls = append(calls, time.Now())
+			return 0, errFail
+		})
+		if !errors.Is(err, errFail) {
+			t.Fatalf("Retry error = %v, want %v", err, errFail)
+		}
+		if len(calls) != attempts {
+			t.Fatalf("op called %d times, want %d", len(calls), attempts)
+		}
+		delay := retryBaseDelay
+		for i := 1; i < len(calls); i++ {
+			if gap := calls[i].Sub(calls[i-1]); gap < delay || gap >= 2*delay {
+				t.Errorf("wait before attempt %d = %v, want in [%v, %v)", i+1, gap, delay, 2*delay)
+			}
+			delay = min(2*delay, retryMaxDelay)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+//go:build !go1.25
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// rootChmod changes the mode of the named file within root. Go 1.24 has no
+// os.Root.Chmod, so the file is opened through root, which still refuses
+// paths that escape it, and its mode is changed through the open handle.
+//
+// Opening needs read permission, which a locked file or directory lacks. In
+// that case rootChmod checks with root.Lstat that name resolves inside root
+// and is not a symlink, then changes it by its host path. Unlike
+// os.Root.Chmod that fallback is not atomic: a concurrent rename within the
+// root can redirect the host path between the check and the change.
+func rootChmod(root *os.Root, name string, mode os.FileMode) error {
+	f, err := root.Open(name)
+	if err == nil {
+		defer f.Close()
+		return f.Chmod(mode)
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	fi, err := root.Lstat(name)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		return &fs.PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+	}
+	return os.Chmod(filepath.Join(root.Name(), name), mode)
+}
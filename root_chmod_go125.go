@@ -0,0 +1,11 @@
+//go:build go1.25
+
+package main
+
+import "os"
+
+// rootChmod changes the mode of the named file within root using
+// os.Root.Chmod, which was added in Go 1.25.
+func rootChmod(root *os.Root, name string, mode os.FileMode) error {
+	return root.Chmod(name, mode)
+}
@@ -0,0 +1,22 @@
+//go:build !go1.25
+
+package main
+
+import "sync"
+
+// waitGroupStyle names the way goAll launches goroutines on this toolchain.
+const waitGroupStyle = "WaitGroup.Add/Done"
+
+// goAll runs each of fns in its own goroutine and waits for all of them to
+// return. Go 1.24 has no WaitGroup.Go, so it uses the Add/Done pattern.
+func goAll(fns ...func()) {
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	wg.Wait()
+}
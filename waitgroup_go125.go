@@ -0,0 +1,18 @@
+//go:build go1.25
+
+package main
+
+import "sync"
+
+// waitGroupStyle names the way goAll launches goroutines on this toolchain.
+const waitGroupStyle = "WaitGroup.Go"
+
+// goAll runs each of fns in its own goroutine and waits for all of them to
+// return, using WaitGroup.Go, which was added in Go 1.25.
+func goAll(fns ...func()) {
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		wg.Go(fn)
+	}
+	wg.Wait()
+}